@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAPIKey dikembalikan jika API key tidak dikenal, dicabut, atau kedaluwarsa
+var ErrInvalidAPIKey = errors.New("API key tidak valid")
+
+// apiKeyPrefix menandai API key aplikasi ini agar mudah dikenali, misalnya oleh secret scanner
+const apiKeyPrefix = "cbk"
+
+// APIKeyScopes adalah izin yang bisa diberikan ke API key
+var APIKeyScopes = []Permission{PermBooksRead, PermBooksWrite, PermImport}
+
+// ParseScopes memeriksa bahwa setiap nama adalah scope API key yang dikenal.
+// Scope ganda dibuang dan urutannya mengikuti APIKeyScopes.
+func ParseScopes(names []string) ([]Permission, error) {
+	if len(names) == 0 {
+		return nil, errors.New("minimal satu scope dibutuhkan")
+	}
+	requested := make(map[Permission]bool)
+	for _, name := range names {
+		perm := Permission(strings.TrimSpace(name))
+		if !isAPIKeyScope(perm) {
+			return nil, fmt.Errorf("scope %q tidak dikenal (pilihan: books:read, books:write, import)", name)
+		}
+		requested[perm] = true
+	}
+	var scopes []Permission
+	for _, scope := range APIKeyScopes {
+		if requested[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func isAPIKeyScope(perm Permission) bool {
+	for _, scope := range APIKeyScopes {
+		if scope == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAPIKey membuat API key baru berformat cbk_<keyID>_<rahasia>. keyID bersifat publik
+// dan dipakai untuk mencari key di database; hanya hash dari key lengkap yang disimpan.
+func GenerateAPIKey() (key, keyID, hash string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	keyID = hex.EncodeToString(id)
+	key = apiKeyPrefix + "_" + keyID + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, keyID, HashAPIKey(key), nil
+}
+
+// APIKeyID mengambil keyID dari API key lengkap
+func APIKeyID(key string) (string, bool) {
+	// Rahasia base64url bisa berisi "_", jadi hanya dua pemisah pertama yang dipakai
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashAPIKey mengembalikan hash SHA-256 (hex) dari API key. API key berisi 256 bit acak,
+// jadi hash cepat sudah cukup dan pemeriksaan per request tetap murah.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyMatches membandingkan API key dengan hash tersimpan dalam waktu konstan
+func APIKeyMatches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	keyring    *Keyring
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+)
+
+// Configure menyiapkan kunci dan masa berlaku token dari konfigurasi autentikasi.
+// Tanpa JWT_SIGNING_KEYS, kunci acak dibuat sehingga token hanya berlaku di proses ini.
+func Configure(cfg config.AuthConfig) error {
+	pairs, err := cfg.ParseSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	var keys []Key
+	for _, pair := range pairs {
+		keys = append(keys, Key{ID: pair[0], Secret: []byte(pair[1])})
+	}
+	if len(keys) == 0 {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		keys = []Key{{ID: "ephemeral-" + hex.EncodeToString(secret[:4]), Secret: secret}}
+		slog.Warn("JWT_SIGNING_KEYS kosong, memakai kunci acak; token tidak berlaku lagi setelah restart")
+	}
+
+	ring, err := NewKeyring(keys...)
+	if err != nil {
+		return err
+	}
+	keyring, issuer = ring, cfg.Issuer
+	accessTTL, refreshTTL = cfg.AccessTTL, cfg.RefreshTTL
+	sessionTTL, sessionSecure = cfg.SessionTTL, cfg.SessionCookieSecure
+	slog.Info("Autentikasi JWT aktif", "signing_kid", keys[0].ID, "keys", len(keys),
+		"access_ttl", accessTTL, "refresh_ttl", refreshTTL, "public_reads", cfg.PublicReads)
+	return nil
+}
+
+// @Description Pasangan token hasil login atau refresh
+// TokenPair adalah token akses dan refresh yang dikirim ke klien
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"`
+}
+
+// IssueTokens menerbitkan token akses dan refresh untuk pengguna
+func IssueTokens(userID int, username string, role Role) (TokenPair, error) {
+	now := time.Now()
+	claims := Claims{
+		Issuer:   issuer,
+		Subject:  strconv.Itoa(userID),
+		Username: username,
+		Role:     string(role),
+		IssuedAt: now.Unix(),
+	}
+
+	access := claims
+	access.Type, access.ExpiresAt, access.ID = TokenAccess, now.Add(accessTTL).Unix(), newTokenID()
+	accessToken, err := keyring.Sign(access)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refresh := claims
+	refresh.Type, refresh.ExpiresAt, refresh.ID = TokenRefresh, now.Add(refreshTTL).Unix(), newTokenID()
+	refreshToken, err := keyring.Sign(refresh)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTTL.Seconds()),
+	}, nil
+}
+
+// ParseToken memverifikasi token berjenis tokenType (TokenAccess, TokenRefresh, atau TokenSession)
+// dan mengembalikan pengguna yang tercantum di dalamnya
+func ParseToken(token, tokenType string) (Principal, error) {
+	claims, err := keyring.Verify(token, issuer, tokenType, time.Now())
+	if err != nil {
+		return Principal{}, err
+	}
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	role, err := ParseRole(claims.Role)
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	return Principal{UserID: id, Username: claims.Username, Role: role}, nil
+}
+
+// Skema header Authorization yang didukung
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// Authorization memecah header "Authorization: <skema> <kredensial>". Skema dikembalikan
+// dalam bentuk kanonis (SchemeBearer atau SchemeAPIKey) jika dikenal.
+func Authorization(r *http.Request) (scheme, credentials string, ok bool) {
+	scheme, credentials, ok = strings.Cut(r.Header.Get("Authorization"), " ")
+	credentials = strings.TrimSpace(credentials)
+	if !ok || credentials == "" {
+		return "", "", false
+	}
+	for _, known := range []string{SchemeBearer, SchemeAPIKey} {
+		if strings.EqualFold(scheme, known) {
+			return known, credentials, true
+		}
+	}
+	return scheme, credentials, true
+}
+
+// Principal adalah pemanggil yang terautentikasi pada sebuah request: pengguna yang login
+// dengan token akses, atau klien mesin dengan API key (APIKeyID bukan 0)
+type Principal struct {
+	UserID   int
+	Username string
+	Role     Role
+
+	APIKeyID   int
+	APIKeyName string
+	Scopes     []Permission
+}
+
+// Can melaporkan apakah pemanggil memiliki izin perm. API key hanya memiliki izin
+// dari scope-nya, tidak mewarisi peran pembuatnya.
+func (p Principal) Can(perm Permission) bool {
+	if p.APIKeyID != 0 {
+		for _, scope := range p.Scopes {
+			if scope == perm {
+				return true
+			}
+		}
+		return false
+	}
+	return p.Role.Can(perm)
+}
+
+// Permissions mengembalikan semua izin yang dimiliki pemanggil
+func (p Principal) Permissions() []Permission {
+	perms := []Permission{}
+	// Admin memiliki semua izin, jadi daftarnya sekaligus menjadi daftar semua izin
+	for _, perm := range rolePermissions[RoleAdmin] {
+		if p.Can(perm) {
+			perms = append(perms, perm)
+		}
+	}
+	return perms
+}
+
+// Name mengembalikan username atau nama API key pemanggil
+func (p Principal) Name() string {
+	if p.APIKeyID != 0 {
+		return p.APIKeyName
+	}
+	return p.Username
+}
+
+// ID mengembalikan identitas stabil pemanggil ("user:<id>" atau "apikey:<id>")
+// untuk log audit dan kebijakan rate limit
+func (p Principal) ID() string {
+	if p.APIKeyID != 0 {
+		return "apikey:" + strconv.Itoa(p.APIKeyID)
+	}
+	return "user:" + strconv.Itoa(p.UserID)
+}
+
+type principalKey struct{}
+
+// WithPrincipal menyimpan pengguna terautentikasi ke ctx
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext mengambil pengguna terautentikasi dari ctx
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
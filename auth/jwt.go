@@ -0,0 +1,171 @@
+// Package auth menangani hashing password, penerbitan dan verifikasi JWT (HS256),
+// serta identitas pengguna yang terautentikasi di dalam context request.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Jenis token yang diterbitkan
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+	TokenSession = "session"
+)
+
+// Error verifikasi token
+var (
+	ErrInvalidToken = errors.New("token tidak valid")
+	ErrExpiredToken = errors.New("token sudah kedaluwarsa")
+)
+
+// Key adalah satu kunci HMAC beserta ID-nya (kid di header JWT)
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring menyimpan kunci penandatangan JWT. Kunci pertama dipakai untuk menandatangani,
+// semua kunci diterima saat verifikasi sehingga token lama tetap berlaku selama rotasi.
+type Keyring struct {
+	keys []Key
+}
+
+// NewKeyring membuat Keyring dari kunci-kunci dengan urutan prioritas
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("minimal satu kunci penandatangan dibutuhkan")
+	}
+	return &Keyring{keys: keys}, nil
+}
+
+// KeyIDs mengembalikan kid semua kunci, diawali kunci aktif
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, len(k.keys))
+	for i, key := range k.keys {
+		ids[i] = key.ID
+	}
+	return ids
+}
+
+func (k *Keyring) lookup(kid string) (Key, bool) {
+	for _, key := range k.keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Claims adalah isi token. Subject berisi ID pengguna; Role ikut disimpan agar izin bisa
+// diperiksa tanpa query database, dan dibaca ulang dari database saat refresh.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Username  string `json:"name,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// Sign menandatangani claims dengan kunci aktif
+func (k *Keyring) Sign(claims Claims) (string, error) {
+	return k.signPayload(claims)
+}
+
+// Verify memeriksa tanda tangan, penerbit, jenis, dan masa berlaku token
+func (k *Keyring) Verify(token, issuer, tokenType string, now time.Time) (Claims, error) {
+	var claims Claims
+	if err := k.verifyPayload(token, &claims); err != nil {
+		return Claims{}, err
+	}
+	if claims.Issuer != issuer || claims.Type != tokenType || claims.Subject == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// signPayload membuat JWS HS256 dari payload apa pun yang bisa di-marshal ke JSON
+func (k *Keyring) signPayload(payload interface{}) (string, error) {
+	key := k.keys[0]
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	unsigned := b64.EncodeToString(h) + "." + b64.EncodeToString(c)
+	return unsigned + "." + b64.EncodeToString(sign(key.Secret, unsigned)), nil
+}
+
+// verifyPayload memeriksa tanda tangan token lalu mendekode payload-nya ke v
+func (k *Keyring) verifyPayload(token string, v interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return ErrInvalidToken
+	}
+	key, ok := k.lookup(h.Kid)
+	if !ok {
+		return ErrInvalidToken
+	}
+	signature, err := b64.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key.Secret, parts[0]+"."+parts[1])) {
+		return ErrInvalidToken
+	}
+	if err := decodeSegment(parts[1], v); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func sign(secret []byte, data string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := b64.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// newTokenID membuat jti acak untuk setiap token
+func newTokenID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand gagal: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
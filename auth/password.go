@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Batas panjang password. bcrypt hanya memakai 72 byte pertama, jadi password
+// yang lebih panjang ditolak daripada dipotong diam-diam.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// passwordCost adalah cost bcrypt untuk hash baru
+const passwordCost = 12
+
+// NoPassword adalah nilai password_hash untuk akun yang hanya bisa login lewat OIDC.
+// Nilai ini bukan hash bcrypt, jadi tidak ada password yang cocok dengannya.
+const NoPassword = "!"
+
+// dummyHash dipakai saat pengguna tidak ditemukan agar waktu respons login
+// tidak membocorkan apakah username terdaftar
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("crud-buku-go"), passwordCost)
+
+// HashPassword membuat hash bcrypt dari password
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password minimal %d karakter", MinPasswordLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return "", fmt.Errorf("password maksimal %d byte", MaxPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword membandingkan password dengan hash. Hash kosong (pengguna tidak
+// ditemukan) dan NoPassword tetap diproses terhadap dummyHash dan selalu menghasilkan false.
+func CheckPassword(hash, password string) bool {
+	if hash == "" || hash == NoPassword {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Role adalah peran pengguna yang menentukan izinnya
+type Role string
+
+// Peran yang dikenal, dari yang paling terbatas
+const (
+	RoleReader    Role = "reader"
+	RoleLibrarian Role = "librarian"
+	RoleAdmin     Role = "admin"
+)
+
+// Roles adalah semua peran yang dikenal, dari yang paling terbatas
+var Roles = []Role{RoleReader, RoleLibrarian, RoleAdmin}
+
+// ParseRole memeriksa bahwa name adalah peran yang dikenal
+func ParseRole(name string) (Role, error) {
+	for _, role := range Roles {
+		if string(role) == name {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf("peran %q tidak dikenal (pilihan: reader, librarian, admin)", name)
+}
+
+// Permission adalah izin untuk satu jenis tindakan
+type Permission string
+
+// Izin yang dipakai deklarasi rute
+const (
+	PermBooksRead     Permission = "books:read"
+	PermReviewsWrite  Permission = "reviews:write"
+	PermBooksWrite    Permission = "books:write"
+	PermImport        Permission = "import"
+	PermLoansManage   Permission = "loans:manage"
+	PermUsersManage   Permission = "users:manage"
+	PermAPIKeysManage Permission = "apikeys:manage"
+	PermConfigRead    Permission = "config:read"
+)
+
+// rolePermissions adalah matriks izin per peran. Reader hanya menjelajah dan mengulas,
+// librarian mengelola buku dan peminjaman, admin mengelola pengguna dan konfigurasi.
+var rolePermissions = map[Role][]Permission{
+	RoleReader:    {PermBooksRead, PermReviewsWrite},
+	RoleLibrarian: {PermBooksRead, PermReviewsWrite, PermBooksWrite, PermImport, PermLoansManage},
+	RoleAdmin: {PermBooksRead, PermReviewsWrite, PermBooksWrite, PermImport, PermLoansManage,
+		PermUsersManage, PermAPIKeysManage, PermConfigRead},
+}
+
+// Can melaporkan apakah role memiliki izin perm
+func (role Role) Can(perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteRule adalah deklarasi izin yang dibutuhkan satu rute
+type RouteRule struct {
+	Method     string     `json:"method"`
+	Path       string     `json:"path"`
+	Permission Permission `json:"permission"`
+	// Public berarti rute bisa diakses tanpa token (lihat AUTH_PUBLIC_READS)
+	Public bool `json:"public"`
+}
+
+var (
+	routeRulesMu sync.Mutex
+	routeRules   []RouteRule
+)
+
+// DeclareRoute mencatat izin yang dibutuhkan sebuah rute agar tampil di matriks izin
+func DeclareRoute(rule RouteRule) {
+	routeRulesMu.Lock()
+	defer routeRulesMu.Unlock()
+	routeRules = append(routeRules, rule)
+}
+
+// @Description Matriks izin per peran dan izin yang dibutuhkan setiap rute
+// PermissionMatrix adalah isi endpoint matriks izin. APIKeyScopes adalah izin
+// yang bisa diberikan ke API key; izin API key hanya berasal dari scope-nya.
+type PermissionMatrix struct {
+	Roles        map[Role][]Permission `json:"roles"`
+	APIKeyScopes []Permission          `json:"api_key_scopes"`
+	Routes       []RouteRule           `json:"routes"`
+}
+
+// Matrix mengembalikan salinan matriks izin beserta deklarasi rute, urut berdasarkan path
+func Matrix() PermissionMatrix {
+	roles := make(map[Role][]Permission, len(rolePermissions))
+	for role, perms := range rolePermissions {
+		roles[role] = append([]Permission(nil), perms...)
+	}
+
+	routeRulesMu.Lock()
+	routes := append([]RouteRule(nil), routeRules...)
+	routeRulesMu.Unlock()
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+
+	return PermissionMatrix{Roles: roles, APIKeyScopes: append([]Permission(nil), APIKeyScopes...), Routes: routes}
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SessionCookie adalah nama cookie sesi hasil login OIDC
+const SessionCookie = "crud_buku_session"
+
+var (
+	sessionTTL    time.Duration
+	sessionSecure bool
+)
+
+// IssueSession menerbitkan token sesi untuk pengguna dan menyimpannya di cookie HttpOnly.
+// Cookie memakai SameSite=Lax sehingga tidak ikut terkirim pada POST lintas situs.
+// Peran di dalam token hanya mencerminkan saat login; pemeriksa sesi harus membaca ulang
+// pengguna dari database.
+func IssueSession(w http.ResponseWriter, userID int, username string, role Role) error {
+	now := time.Now()
+	token, err := keyring.Sign(Claims{
+		Issuer:    issuer,
+		Subject:   strconv.Itoa(userID),
+		Username:  username,
+		Role:      string(role),
+		Type:      TokenSession,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(sessionTTL).Unix(),
+		ID:        newTokenID(),
+	})
+	if err != nil {
+		return err
+	}
+	setCookie(w, SessionCookie, "/", token, sessionTTL)
+	return nil
+}
+
+// SessionToken mengambil token sesi dari cookie request
+func SessionToken(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// ClearSession menghapus cookie sesi di browser
+func ClearSession(w http.ResponseWriter) {
+	setCookie(w, SessionCookie, "/", "", -1)
+}
+
+// signedCookie membungkus nilai cookie bertanda tangan. Type berisi nama cookie agar
+// nilai dari satu cookie tidak bisa dipakai sebagai cookie lain.
+type signedCookie struct {
+	Type      string          `json:"typ"`
+	ExpiresAt int64           `json:"exp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SetSignedCookie menyimpan value (JSON) di cookie HttpOnly yang ditandatangani dengan
+// kunci JWT dan berlaku selama ttl. Isinya bisa dibaca klien, jadi jangan simpan rahasia
+// jangka panjang di dalamnya.
+func SetSignedCookie(w http.ResponseWriter, name, path string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	token, err := keyring.signPayload(signedCookie{Type: name, ExpiresAt: time.Now().Add(ttl).Unix(), Data: data})
+	if err != nil {
+		return err
+	}
+	setCookie(w, name, path, token, ttl)
+	return nil
+}
+
+// ReadSignedCookie memeriksa tanda tangan dan masa berlaku cookie name lalu mendekode
+// isinya ke value
+func ReadSignedCookie(r *http.Request, name string, value interface{}) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	var sc signedCookie
+	if err := keyring.verifyPayload(cookie.Value, &sc); err != nil || sc.Type != name {
+		return ErrInvalidToken
+	}
+	if time.Now().Unix() >= sc.ExpiresAt {
+		return ErrExpiredToken
+	}
+	if err := json.Unmarshal(sc.Data, value); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// ClearCookie menghapus cookie name dengan path yang sama seperti saat dibuat
+func ClearCookie(w http.ResponseWriter, name, path string) {
+	setCookie(w, name, path, "", -1)
+}
+
+func setCookie(w http.ResponseWriter, name, path, value string, ttl time.Duration) {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   sessionSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,147 @@
+// Package cache menyediakan antarmuka cache key-value di memori beserta implementasi LRU.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache adalah penyimpanan key-value sementara dengan TTL per entri
+type Cache interface {
+	// Get mengembalikan nilai dan true jika key ada dan belum kedaluwarsa
+	Get(key string) (interface{}, bool)
+	// Set menyimpan nilai; ttl <= 0 berarti memakai TTL bawaan cache
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete menghapus satu key
+	Delete(key string)
+	// Purge menghapus semua entri
+	Purge()
+	// Stats mengembalikan statistik hit/miss sejak cache dibuat
+	Stats() Stats
+}
+
+// Stats berisi statistik pemakaian cache
+type Stats struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	Expired   uint64  `json:"expired"`
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// LRU adalah Cache berkapasitas tetap yang membuang entri paling lama tidak dipakai
+type LRU struct {
+	mu         sync.Mutex
+	capacity   int
+	defaultTTL time.Duration
+	items      map[string]*list.Element
+	order      *list.List
+	stats      Stats
+}
+
+// NewLRU membuat cache LRU dengan kapasitas dan TTL bawaan tertentu
+func NewLRU(capacity int, defaultTTL time.Duration) *LRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity:   capacity,
+		defaultTTL: defaultTTL,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get mengembalikan nilai dan menandainya sebagai yang terakhir dipakai
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.removeElement(elem)
+		c.stats.Expired++
+		c.stats.Misses++
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Set menyimpan nilai dan membuang entri tertua jika kapasitas penuh
+func (c *LRU) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Delete menghapus satu key dari cache
+func (c *LRU) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Purge menghapus semua entri tanpa mereset statistik
+func (c *LRU) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Stats mengembalikan salinan statistik cache saat ini
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.order.Len()
+	stats.Capacity = c.capacity
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (c *LRU) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
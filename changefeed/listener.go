@@ -0,0 +1,98 @@
+// Package changefeed mendengarkan notifikasi perubahan data buku dari PostgreSQL
+// (LISTEN/NOTIFY, lihat migrations/005_books_change_notify.sql) dan membuang cache
+// serta indeks pencarian lokal, sehingga beberapa replika tetap konsisten.
+package changefeed
+
+import (
+	"crud-buku-go/models"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel adalah nama channel NOTIFY yang dipakai trigger books_notify_change
+const Channel = "books_changed"
+
+// Rentang jeda sambung ulang dan interval ping koneksi listener
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	pingInterval         = 90 * time.Second
+)
+
+// Change adalah payload notifikasi dari trigger
+type Change struct {
+	Op string `json:"op"`
+	ID int    `json:"id"`
+}
+
+// Listener memegang satu koneksi LISTEN yang tersambung ulang secara otomatis
+type Listener struct {
+	listener *pq.Listener
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// Start membuka koneksi LISTEN ke dsn dan mulai memproses notifikasi di background
+func Start(dsn string) (*Listener, error) {
+	pl := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			slog.Warn("Listener perubahan buku terputus", "error", err)
+		case pq.ListenerEventConnectionAttemptFailed:
+			slog.Warn("Gagal menyambung ulang listener perubahan buku", "error", err)
+		case pq.ListenerEventReconnected:
+			slog.Info("Listener perubahan buku tersambung kembali")
+		}
+	})
+	if err := pl.Listen(Channel); err != nil {
+		pl.Close()
+		return nil, err
+	}
+
+	l := &Listener{listener: pl, done: make(chan struct{})}
+	l.wg.Add(1)
+	go l.run()
+	slog.Info("Mendengarkan perubahan buku", "channel", Channel)
+	return l, nil
+}
+
+func (l *Listener) run() {
+	defer l.wg.Done()
+	for {
+		select {
+		case <-l.done:
+			return
+		case n := <-l.listener.Notify:
+			if n == nil {
+				// Koneksi baru saja tersambung ulang; notifikasi selama terputus bisa hilang
+				slog.Info("Listener tersambung ulang, membuang seluruh cache lokal")
+				models.PurgeCaches()
+				continue
+			}
+			handle(n.Extra)
+		case <-time.After(pingInterval):
+			go l.listener.Ping()
+		}
+	}
+}
+
+func handle(payload string) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		slog.Warn("Payload notifikasi perubahan buku tidak valid", "payload", payload, "error", err)
+		models.PurgeCaches()
+		return
+	}
+	models.ApplyExternalChange(change.ID)
+}
+
+// Close menghentikan pemrosesan notifikasi dan menutup koneksi LISTEN
+func (l *Listener) Close() error {
+	close(l.done)
+	l.wg.Wait()
+	return l.listener.Close()
+}
@@ -1,81 +1,100 @@
 package config
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
-	"strconv"
 
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var DB *sql.DB
 
-func ConnectDB() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Peringatan: Tidak dapat memuat file .env, menggunakan variabel environment sistem jika ada.")
-	}
-
-	dbHost := os.Getenv("DB_HOST")
-	dbPortStr := os.Getenv("DB_PORT")
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-
-	dbPort, err := strconv.Atoi(dbPortStr)
-	if err != nil {
-		log.Fatalf("Port database tidak valid: %v", err)
-	}
-
-	initialConnStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=postgres sslmode=disable",
-		dbHost, dbPort, dbUser, dbPassword)
+// DSN adalah connection string database aplikasi, dipakai oleh koneksi
+// tambahan di luar pool seperti listener LISTEN/NOTIFY
+var DSN string
 
-	tempDB, err := sql.Open("postgres", initialConnStr)
-	if err != nil {
-		log.Fatalf("Gagal terhubung ke server PostgreSQL: %v", err)
+// ConnectDB menghubungkan aplikasi ke database memakai konfigurasi di App.
+// Load harus dipanggil terlebih dahulu. Untuk PostgreSQL, database hanya dibuat
+// otomatis jika DB_AUTO_CREATE aktif; selain itu jalankan perintah provision lebih dulu.
+func ConnectDB() {
+	if App.Database.Driver == DriverSQLite {
+		connectSQLite()
+		return
 	}
-	defer tempDB.Close()
 
-	rows, err := tempDB.Query(fmt.Sprintf("SELECT 1 FROM pg_database WHERE datname = '%s'", dbName))
-	if err != nil {
-		log.Fatalf("Gagal memeriksa keberadaan database: %v", err)
-	}
-	defer rows.Close()
+	dbConfig := App.Database
+	dbName := dbConfig.DatabaseName()
 
-	if !rows.Next() {
+	retry := loadRetryPolicy()
+	ctx, cancel := context.WithTimeout(context.Background(), retry.Deadline)
+	defer cancel()
 
-		log.Printf("Database '%s' tidak ditemukan, mencoba membuat...", dbName)
-		_, err = tempDB.Exec(fmt.Sprintf("CREATE DATABASE %s", dbName))
+	if dbConfig.AutoCreate {
+		err := withRetry(ctx, retry, "server PostgreSQL", func() error {
+			return ensureDatabase(ctx, dbConfig.AdminConnString("postgres"), dbName, "")
+		})
 		if err != nil {
-			log.Fatalf("Gagal membuat database '%s': %v", dbName, err)
+			log.Fatalf("Gagal menyiapkan database '%s': %v", dbName, err)
 		}
-		log.Printf("Database '%s' berhasil dibuat.", dbName)
-	} else {
-		log.Printf("Database '%s' sudah ada.", dbName)
 	}
 
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		dbHost, dbPort, dbUser, dbPassword, dbName)
+	connStr := dbConfig.ConnString("")
 
 	database, err := sql.Open("postgres", connStr)
 	if err != nil {
 		log.Fatalf("Gagal terhubung ke database: %v", err)
 	}
+	configurePool(database)
 
-	err = database.Ping()
+	err = withRetry(ctx, retry, "database", func() error {
+		return database.PingContext(ctx)
+	})
 	if err != nil {
-		log.Fatalf("Gagal melakukan ping ke database: %v", err)
+		log.Fatalf("Gagal melakukan ping ke database '%s': %v", dbName, err)
 	}
 
 	DB = database
+	DSN = connStr
 	log.Println("Berhasil terhubung ke database PostgreSQL!")
 
 	createTable()
 }
 
+// ensureDatabase membuat database dbName jika belum ada. owner kosong berarti
+// pemiliknya adalah role yang dipakai initialConnStr.
+func ensureDatabase(ctx context.Context, initialConnStr, dbName, owner string) error {
+	tempDB, err := sql.Open("postgres", initialConnStr)
+	if err != nil {
+		return fmt.Errorf("gagal terhubung ke server PostgreSQL: %w", err)
+	}
+	defer tempDB.Close()
+
+	var exists bool
+	err = tempDB.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa keberadaan database: %w", err)
+	}
+	if exists {
+		log.Printf("Database '%s' sudah ada.", dbName)
+		return nil
+	}
+
+	log.Printf("Database '%s' tidak ditemukan, mencoba membuat...", dbName)
+	// CREATE DATABASE tidak menerima parameter, jadi nama dikutip sebagai identifier
+	stmt := "CREATE DATABASE " + pq.QuoteIdentifier(dbName)
+	if owner != "" {
+		stmt += " OWNER " + pq.QuoteIdentifier(owner)
+	}
+	if _, err := tempDB.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("gagal membuat database '%s': %w", dbName, err)
+	}
+	log.Printf("Database '%s' berhasil dibuat.", dbName)
+	return nil
+}
+
 func createTable() {
 	createTableSQL := `
 	CREATE TABLE IF NOT EXISTS books (
@@ -92,4 +111,62 @@ func createTable() {
 		log.Fatalf("Gagal membuat tabel 'books': %v", err)
 	}
 	log.Println("Tabel 'books' siap digunakan.")
+
+	createUsersSQL := `
+	CREATE TABLE IF NOT EXISTS users (
+		id SERIAL PRIMARY KEY,
+		username VARCHAR(64) NOT NULL UNIQUE,
+		password_hash VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := DB.Exec(createUsersSQL); err != nil {
+		log.Fatalf("Gagal membuat tabel 'users': %v", err)
+	}
+	// Kolom role ditambahkan belakangan; pengguna lama menjadi reader
+	if _, err := DB.Exec("ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'reader'"); err != nil {
+		log.Fatalf("Gagal menambahkan kolom 'role' ke tabel 'users': %v", err)
+	}
+	if _, err := DB.Exec("ALTER TABLE users ADD COLUMN IF NOT EXISTS external_id VARCHAR(255)"); err != nil {
+		log.Fatalf("Gagal menambahkan kolom 'external_id' ke tabel 'users': %v", err)
+	}
+	if _, err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_id ON users (external_id)"); err != nil {
+		log.Fatalf("Gagal membuat index 'idx_users_external_id': %v", err)
+	}
+	log.Println("Tabel 'users' siap digunakan.")
+
+	createAPIKeysSQL := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		key_id VARCHAR(32) NOT NULL UNIQUE,
+		key_hash CHAR(64) NOT NULL,
+		scopes VARCHAR(255) NOT NULL,
+		created_by INT REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		expires_at TIMESTAMP,
+		last_used_at TIMESTAMP,
+		revoked_at TIMESTAMP
+	);`
+
+	if _, err := DB.Exec(createAPIKeysSQL); err != nil {
+		log.Fatalf("Gagal membuat tabel 'api_keys': %v", err)
+	}
+	log.Println("Tabel 'api_keys' siap digunakan.")
+
+	// Bucket rate limit bersama (RATE_LIMIT_STORE=postgres). UNLOGGED karena isinya boleh
+	// hilang saat crash: paling buruk semua klien mendapat kuota penuh lagi.
+	createRateLimitsSQL := `
+	CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+		key VARCHAR(255) PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL,
+		full_at TIMESTAMPTZ NOT NULL
+	);`
+
+	if _, err := DB.Exec(createRateLimitsSQL); err != nil {
+		log.Fatalf("Gagal membuat tabel 'rate_limits': %v", err)
+	}
+	log.Println("Tabel 'rate_limits' siap digunakan.")
 }
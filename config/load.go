@@ -0,0 +1,264 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue menggantikan nilai rahasia pada dump konfigurasi
+const redactedValue = "******"
+
+// setting adalah satu field daun Config beserta metadatanya
+type setting struct {
+	path   string // path YAML, misalnya "database.host"
+	env    string
+	flag   string
+	def    string
+	secret bool
+	value  reflect.Value
+}
+
+// settings mengembalikan seluruh field daun c secara berurutan
+func settings(c *Config) []setting {
+	var out []setting
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			path := prefix + field.Tag.Get("yaml")
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), path+".")
+				continue
+			}
+			env := field.Tag.Get("env")
+			out = append(out, setting{
+				path:   path,
+				env:    env,
+				flag:   strings.ReplaceAll(strings.ToLower(env), "_", "-"),
+				def:    field.Tag.Get("default"),
+				secret: field.Tag.Get("secret") == "true",
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem(), "")
+	return out
+}
+
+// set mengisi field dari representasi string-nya
+func (s setting) set(raw string) error {
+	v := s.value
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("durasi tidak valid %q", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("bilangan bulat tidak valid %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("angka tidak valid %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("boolean tidak valid %q", raw)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("tipe %s tidak didukung", v.Type())
+	}
+	return nil
+}
+
+// Defaults mengembalikan Config yang berisi nilai bawaan dari tag default
+func Defaults() Config {
+	var c Config
+	for _, s := range settings(&c) {
+		if s.def == "" {
+			continue
+		}
+		if err := s.set(s.def); err != nil {
+			panic(fmt.Sprintf("config: default %s tidak valid: %v", s.env, err))
+		}
+	}
+	return c
+}
+
+// rawFlag menyimpan nilai flag apa adanya; penerapannya ditunda sampai
+// file dan environment sudah dibaca agar urutan prioritas terjaga.
+type rawFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *rawFlag) String() string     { return f.value }
+func (f *rawFlag) Set(v string) error { f.value = v; return nil }
+func (f *rawFlag) IsBoolFlag() bool   { return f.isBool }
+
+// Load membaca konfigurasi dengan urutan prioritas (yang terakhir menang):
+// nilai bawaan, file YAML (-config atau CONFIG_FILE), environment (termasuk .env),
+// lalu flag command-line. Variabel NAMA_FILE dibaca sebagai isi file untuk NAMA,
+// sehingga secret bisa dipasang dari file. Semua kesalahan dikumpulkan dan
+// dikembalikan sekaligus. Jika berhasil, hasilnya juga disimpan di App.
+func Load(args []string) (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Peringatan: Tidak dapat memuat file .env, menggunakan variabel environment sistem jika ada.")
+	}
+
+	cfg := Defaults()
+	all := settings(&cfg)
+
+	fs := flag.NewFlagSet("crud-buku-go", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path file konfigurasi YAML")
+	flags := make(map[string]*rawFlag, len(all))
+	for _, s := range all {
+		f := &rawFlag{isBool: s.value.Kind() == reflect.Bool}
+		flags[s.flag] = f
+		fs.Var(f, s.flag, fmt.Sprintf("%s (env %s)", s.path, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var problems []error
+	if *configFile != "" {
+		if err := loadYAML(*configFile, &cfg); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for _, s := range all {
+		raw, source, ok, err := lookupEnv(s.env)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := s.set(raw); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %v", source, err))
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range all {
+			if s.flag == f.Name {
+				if err := s.set(flags[s.flag].value); err != nil {
+					problems = append(problems, fmt.Errorf("-%s: %v", s.flag, err))
+				}
+			}
+		}
+	})
+
+	cfg.applyDriverDefaults()
+
+	// Nilai yang gagal diurai tetap memakai nilai sebelumnya, sehingga Validate tetap bisa
+	// melaporkan kesalahan lain pada konfigurasi yang sama
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+
+	App = cfg
+	return &cfg, nil
+}
+
+// lookupEnv membaca KEY, atau isi file yang ditunjuk KEY_FILE jika KEY tidak diatur
+func lookupEnv(key string) (value, source string, ok bool, err error) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, key, true, nil
+	}
+	path, ok := os.LookupEnv(key + "_FILE")
+	if !ok || path == "" {
+		return "", "", false, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false, fmt.Errorf("%s_FILE: gagal membaca %s: %v", key, path, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), key + "_FILE", true, nil
+}
+
+func loadYAML(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("file konfigurasi: %v", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("file konfigurasi %s: %v", path, err)
+	}
+	return nil
+}
+
+// Redacted mengembalikan konfigurasi sebagai map bertingkat dengan nilai rahasia disamarkan
+func (c Config) Redacted() map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, s := range settings(&c) {
+		var value interface{} = s.value.Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		if s.secret {
+			value = redact(s.value.String())
+		}
+
+		node := out
+		parts := strings.Split(s.path, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return out
+}
+
+// redact menyamarkan nilai rahasia; untuk URL hanya password yang disamarkan
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), redactedValue)
+		}
+		return u.String()
+	}
+	return redactedValue
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// Nilai env yang gagal diurai tidak boleh menyembunyikan kesalahan validasi lain
+func TestLoadReportsParseAndValidationErrors(t *testing.T) {
+	t.Setenv("APP_PORT", "bukan-angka")
+	t.Setenv("SHUTDOWN_DELAY", "-1s")
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatal("Load seharusnya gagal")
+	}
+	for _, want := range []string{"APP_PORT", "SHUTDOWN_DELAY tidak boleh negatif"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error tidak menyebut %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadPicksSearchBackendForDriver(t *testing.T) {
+	previous := App
+	t.Cleanup(func() { App = previous })
+
+	tests := []struct {
+		driver, backend, want string
+	}{
+		{"sqlite", "", "sqlite"},
+		{"postgres", "", "postgres"},
+		{"sqlite", "memory", "memory"},
+	}
+	for _, tt := range tests {
+		t.Setenv("DB_DRIVER", tt.driver)
+		t.Setenv("DB_NAME", "crud_buku_db")
+		t.Setenv("SEARCH_BACKEND", tt.backend)
+		cfg, err := Load(nil)
+		if err != nil {
+			t.Fatalf("DB_DRIVER=%s SEARCH_BACKEND=%q: %v", tt.driver, tt.backend, err)
+		}
+		if cfg.Search.Backend != tt.want {
+			t.Errorf("DB_DRIVER=%s SEARCH_BACKEND=%q: backend = %q, seharusnya %q", tt.driver, tt.backend, cfg.Search.Backend, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+)
+
+// postgresMigrationProbes memetakan migrasi PostgreSQL (dijalankan manual dengan psql,
+// tanpa tabel pencatat) ke query yang bernilai true jika hasil migrasinya sudah ada.
+var postgresMigrationProbes = []struct {
+	version string
+	probe   string
+}{
+	{"002_add_search_indexes", `SELECT EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_name = 'books' AND column_name = 'search_vector')`},
+	{"003_add_trigram_search", `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')
+		AND EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_books_title_trgm')`},
+	{"004_weighted_search_vector", `SELECT EXISTS (SELECT 1 FROM pg_proc
+		WHERE proname = 'books_search_vector_update' AND prosrc LIKE '%setweight%')`},
+	{"005_books_change_notify", `SELECT EXISTS (SELECT 1 FROM pg_trigger
+		WHERE tgname = 'books_notify_change_trigger')`},
+}
+
+// PendingMigrations mengembalikan versi migrasi yang belum diterapkan ke DB
+func PendingMigrations(ctx context.Context) ([]string, error) {
+	if App.Database.Driver == DriverSQLite {
+		return pendingSQLiteMigrations(ctx, DB)
+	}
+
+	pending := []string{}
+	for _, m := range postgresMigrationProbes {
+		var applied bool
+		if err := DB.QueryRowContext(ctx, m.probe).Scan(&applied); err != nil {
+			return nil, err
+		}
+		if !applied {
+			pending = append(pending, m.version)
+		}
+	}
+	return pending, nil
+}
+
+func pendingSQLiteMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	files, err := sqliteMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool)
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, file := range files {
+		version := migrationVersion(file)
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
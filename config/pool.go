@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RetryPolicy mengatur percobaan ulang koneksi database saat startup
+type RetryPolicy struct {
+	Deadline       time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// loadRetryPolicy mengambil kebijakan retry dari konfigurasi database
+func loadRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Deadline:       App.Database.ConnectDeadline,
+		InitialBackoff: App.Database.InitialBackoff,
+		MaxBackoff:     App.Database.MaxBackoff,
+	}
+}
+
+// withRetry menjalankan fn sampai berhasil atau ctx berakhir, dengan jeda
+// exponential backoff (plus jitter) di antara percobaan. Hanya error sementara
+// yang dicoba ulang; error permanen langsung dikembalikan.
+func withRetry(ctx context.Context, policy RetryPolicy, what string, fn func() error) error {
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) {
+			return err
+		}
+
+		// Jitter hingga 20% agar beberapa replika tidak mencoba bersamaan
+		wait := backoff + time.Duration(rand.Int63n(int64(backoff)/5+1))
+		log.Printf("Percobaan %d terhubung ke %s gagal: %v (mencoba lagi dalam %v)", attempt, what, err, wait.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// isTransient melaporkan apakah err kemungkinan hilang sendiri dengan mencoba lagi:
+// server belum menerima koneksi, jaringan belum siap, atau PostgreSQL masih startup.
+// Kegagalan autentikasi, database yang tidak ada, dan salah konfigurasi SSL/TLS
+// tidak akan berubah dengan menunggu.
+func isTransient(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "57P03" // cannot_connect_now
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// configurePool mengatur ukuran dan umur koneksi pool dari konfigurasi database
+func configurePool(db *sql.DB) {
+	maxOpen := App.Database.MaxOpenConns
+	maxIdle := App.Database.MaxIdleConns
+	maxLifetime := App.Database.ConnMaxLifetime
+	maxIdleTime := App.Database.ConnMaxIdleTime
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(maxLifetime)
+	db.SetConnMaxIdleTime(maxIdleTime)
+	log.Printf("Pool database: maks %d koneksi terbuka, %d idle, umur maks %v, idle maks %v",
+		maxOpen, maxIdle, maxLifetime, maxIdleTime)
+}
+
+// PoolStats adalah ringkasan sql.DBStats dalam bentuk yang mudah dibaca sebagai JSON
+type PoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     float64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64   `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64   `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed"`
+}
+
+// GetPoolStats mengembalikan statistik pool koneksi DB saat ini
+func GetPoolStats() PoolStats {
+	if DB == nil {
+		return PoolStats{}
+	}
+	s := DB.Stats()
+	return PoolStats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDurationMs:     float64(s.WaitDuration) / float64(time.Millisecond),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
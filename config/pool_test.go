@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestWithRetryOnlyRetriesTransientErrors(t *testing.T) {
+	policy := RetryPolicy{Deadline: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"koneksi ditolak", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"koneksi ditolak dibungkus", fmt.Errorf("gagal terhubung: %w", syscall.ECONNREFUSED), true},
+		{"server masih startup", &pq.Error{Code: "57P03"}, true},
+		{"autentikasi gagal", &pq.Error{Code: "28P01"}, false},
+		{"database tidak ada", fmt.Errorf("gagal memeriksa database: %w", &pq.Error{Code: "3D000"}), false},
+		{"SSL tidak aktif", errors.New("pq: SSL is not enabled on the server"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := withRetry(context.Background(), policy, "database", func() error {
+				attempts++
+				if attempts < 3 {
+					return tt.err
+				}
+				return nil
+			})
+			if tt.transient && (err != nil || attempts != 3) {
+				t.Errorf("error sementara: %d percobaan, error %v; seharusnya berhasil di percobaan ke-3", attempts, err)
+			}
+			if !tt.transient && (!errors.Is(err, tt.err) || attempts != 1) {
+				t.Errorf("error permanen: %d percobaan, error %v; seharusnya langsung dikembalikan", attempts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+// requiredExtensions adalah ekstensi PostgreSQL yang dibutuhkan fitur pencarian
+var requiredExtensions = []string{"pg_trgm", "unaccent"}
+
+// Provision menyiapkan server PostgreSQL untuk aplikasi: membuat role aplikasi,
+// database, dan ekstensi yang dibutuhkan. Aman dijalankan berulang kali.
+// Koneksi memakai DB_ADMIN_URL jika diatur, selain itu kredensial aplikasi.
+func Provision(ctx context.Context) error {
+	dbConfig := App.Database
+	if dbConfig.Driver != DriverPostgres {
+		return fmt.Errorf("provision hanya berlaku untuk DB_DRIVER=%s; database SQLite dibuat otomatis saat startup", DriverPostgres)
+	}
+	dbName := dbConfig.DatabaseName()
+	role := dbConfig.Username()
+
+	admin, err := sql.Open("postgres", dbConfig.AdminConnString("postgres"))
+	if err != nil {
+		return fmt.Errorf("gagal terhubung ke server PostgreSQL: %w", err)
+	}
+	defer admin.Close()
+
+	retry := loadRetryPolicy()
+	if err := withRetry(ctx, retry, "server PostgreSQL", func() error {
+		return admin.PingContext(ctx)
+	}); err != nil {
+		return fmt.Errorf("gagal terhubung ke server PostgreSQL: %w", err)
+	}
+
+	if role != "" {
+		if err := ensureRole(ctx, admin, role, dbConfig.userPassword()); err != nil {
+			return err
+		}
+	}
+	if err := ensureDatabase(ctx, dbConfig.AdminConnString("postgres"), dbName, role); err != nil {
+		return err
+	}
+
+	target, err := sql.Open("postgres", dbConfig.AdminConnString(dbName))
+	if err != nil {
+		return fmt.Errorf("gagal terhubung ke database '%s': %w", dbName, err)
+	}
+	defer target.Close()
+
+	for _, ext := range requiredExtensions {
+		if _, err := target.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS "+pq.QuoteIdentifier(ext)); err != nil {
+			return fmt.Errorf("gagal membuat ekstensi %s: %w", ext, err)
+		}
+		log.Printf("Ekstensi '%s' siap digunakan.", ext)
+	}
+
+	if role != "" {
+		// Sejak PostgreSQL 15 role biasa tidak lagi boleh membuat objek di schema public
+		if _, err := target.ExecContext(ctx, "GRANT ALL ON SCHEMA public TO "+pq.QuoteIdentifier(role)); err != nil {
+			return fmt.Errorf("gagal memberi hak akses schema public ke '%s': %w", role, err)
+		}
+	}
+
+	log.Printf("Provisioning database '%s' selesai.", dbName)
+	return nil
+}
+
+// ensureRole membuat role login jika belum ada. Password role yang sudah ada tidak diubah.
+func ensureRole(ctx context.Context, admin *sql.DB, role, password string) error {
+	var exists bool
+	err := admin.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1)", role).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa keberadaan role: %w", err)
+	}
+	if exists {
+		log.Printf("Role '%s' sudah ada.", role)
+		return nil
+	}
+
+	// CREATE ROLE tidak menerima parameter, jadi nama dan password dikutip secara eksplisit
+	stmt := "CREATE ROLE " + pq.QuoteIdentifier(role) + " LOGIN"
+	if password != "" {
+		stmt += " PASSWORD " + pq.QuoteLiteral(password)
+	}
+	if _, err := admin.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("gagal membuat role '%s': %w", role, err)
+	}
+	log.Printf("Role '%s' berhasil dibuat.", role)
+	return nil
+}
@@ -0,0 +1,649 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config adalah seluruh konfigurasi aplikasi.
+// Setiap field daun memiliki tag env (nama variabel environment; nama flag diturunkan
+// darinya, misalnya DB_HOST menjadi -db-host), yaml (kunci di file konfigurasi),
+// dan default. Field bertanda secret disamarkan pada dump konfigurasi.
+type Config struct {
+	App       AppConfig       `yaml:"app"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Search    SearchConfig    `yaml:"search"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Log       LogConfig       `yaml:"log"`
+	Auth      AuthConfig      `yaml:"auth"`
+	OIDC      OIDCConfig      `yaml:"oidc"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	CORS      CORSConfig      `yaml:"cors"`
+}
+
+// AppConfig berisi pengaturan server HTTP.
+// ShutdownDelay adalah jeda antara readiness menjadi gagal dan server berhenti menerima
+// koneksi, agar load balancer sempat berhenti mengirim trafik. ShutdownTimeout membatasi
+// waktu menunggu request yang sedang berjalan dan goroutine latar belakang.
+// HealthTimeout membatasi setiap pemeriksaan dependensi di /readyz.
+type AppConfig struct {
+	Port            int           `yaml:"port" env:"APP_PORT" default:"8080"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" default:"5s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	HealthTimeout   time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+}
+
+// DatabaseConfig berisi pengaturan koneksi dan pool database.
+// Jika URL (DATABASE_URL) diisi, Host/Port/User/Password/Name diabaikan.
+// AdminURL hanya dipakai oleh perintah provision dan pembuatan database otomatis.
+// Dengan Driver sqlite, hanya SQLitePath dan pengaturan pool yang dipakai.
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" env:"DB_DRIVER" default:"postgres"`
+	SQLitePath      string        `yaml:"sqlite_path" env:"SQLITE_PATH" default:"crud_buku.db"`
+	URL             string        `yaml:"url" env:"DATABASE_URL" secret:"true"`
+	AdminURL        string        `yaml:"admin_url" env:"DB_ADMIN_URL" secret:"true"`
+	AutoCreate      bool          `yaml:"auto_create" env:"DB_AUTO_CREATE" default:"false"`
+	Host            string        `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port            int           `yaml:"port" env:"DB_PORT" default:"5432"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+	SSLRootCert     string        `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+	SSLCert         string        `yaml:"sslcert" env:"DB_SSLCERT"`
+	SSLKey          string        `yaml:"sslkey" env:"DB_SSLKEY"`
+	ConnectDeadline time.Duration `yaml:"connect_deadline" env:"DB_CONNECT_DEADLINE" default:"60s"`
+	InitialBackoff  time.Duration `yaml:"connect_initial_backoff" env:"DB_CONNECT_INITIAL_BACKOFF" default:"500ms"`
+	MaxBackoff      time.Duration `yaml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF" default:"10s"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"5m"`
+	ListenChanges   bool          `yaml:"listen_changes" env:"BOOK_CHANGE_LISTEN" default:"true"`
+}
+
+// SearchConfig berisi pengaturan backend dan relevansi pencarian.
+// Backend yang kosong diisi saat Load sesuai DB_DRIVER (postgres atau sqlite).
+type SearchConfig struct {
+	Backend        string  `yaml:"backend" env:"SEARCH_BACKEND"`
+	FuzzyThreshold float64 `yaml:"fuzzy_threshold" env:"SEARCH_FUZZY_THRESHOLD" default:"0.3"`
+	BoostTitle     float64 `yaml:"boost_title" env:"SEARCH_BOOST_TITLE" default:"1.0"`
+	BoostAuthor    float64 `yaml:"boost_author" env:"SEARCH_BOOST_AUTHOR" default:"0.4"`
+	BoostYear      float64 `yaml:"boost_year" env:"SEARCH_BOOST_YEAR" default:"0.1"`
+	RecencyWeight  float64 `yaml:"recency_weight" env:"SEARCH_RECENCY_WEIGHT" default:"0.1"`
+	RecencyScale   float64 `yaml:"recency_scale" env:"SEARCH_RECENCY_SCALE" default:"10"`
+}
+
+// CacheConfig berisi pengaturan cache di memori proses
+type CacheConfig struct {
+	BookSize    int           `yaml:"book_size" env:"BOOK_CACHE_SIZE" default:"1000"`
+	BookTTL     time.Duration `yaml:"book_ttl" env:"BOOK_CACHE_TTL" default:"5m"`
+	BookListTTL time.Duration `yaml:"book_list_ttl" env:"BOOK_LIST_CACHE_TTL" default:"30s"`
+	StatsTTL    time.Duration `yaml:"stats_ttl" env:"STATS_CACHE_TTL" default:"30s"`
+}
+
+// TracingConfig berisi pengaturan tracing. Exporter none mematikan tracing;
+// stdout menulis span sebagai JSON ringkas, otlp-file menulis OTLP/JSON ke File.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACE_EXPORTER" default:"none"`
+	File        string  `yaml:"file" env:"TRACE_FILE" default:"traces.jsonl"`
+	ServiceName string  `yaml:"service_name" env:"TRACE_SERVICE_NAME" default:"crud-buku-go"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACE_SAMPLE_RATIO" default:"1"`
+}
+
+// LogConfig berisi format (json atau text) dan level minimal log (debug, info, warn, error)
+type LogConfig struct {
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"json"`
+	Level  string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+}
+
+// AuthConfig berisi pengaturan autentikasi JWT.
+// SigningKeys berisi daftar kunci HMAC "kid:rahasia" dipisah koma; kunci pertama dipakai
+// untuk menandatangani, semua kunci diterima saat verifikasi. Untuk rotasi, tambahkan kunci
+// baru di depan dan hapus kunci lama setelah RefreshTTL berlalu. Jika kosong, kunci acak
+// dibuat saat startup sehingga token tidak berlaku lagi setelah restart.
+// PublicReads membuat endpoint baca buku bisa diakses tanpa token.
+// SessionTTL dan SessionCookieSecure mengatur cookie sesi hasil login OIDC; matikan
+// SessionCookieSecure hanya untuk pengembangan lewat http://localhost.
+type AuthConfig struct {
+	SigningKeys         string        `yaml:"signing_keys" env:"JWT_SIGNING_KEYS" secret:"true"`
+	Issuer              string        `yaml:"issuer" env:"JWT_ISSUER" default:"crud-buku-go"`
+	AccessTTL           time.Duration `yaml:"access_ttl" env:"JWT_ACCESS_TTL" default:"15m"`
+	RefreshTTL          time.Duration `yaml:"refresh_ttl" env:"JWT_REFRESH_TTL" default:"168h"`
+	PublicReads         bool          `yaml:"public_reads" env:"AUTH_PUBLIC_READS" default:"true"`
+	SessionTTL          time.Duration `yaml:"session_ttl" env:"SESSION_TTL" default:"8h"`
+	SessionCookieSecure bool          `yaml:"session_cookie_secure" env:"SESSION_COOKIE_SECURE" default:"true"`
+}
+
+// OIDCConfig berisi pengaturan login OpenID Connect (authorization code + PKCE).
+// Login OIDC aktif jika Issuer diisi. RoleMapping berisi pasangan "nilai=peran" dipisah
+// koma, dicocokkan dengan klaim RoleClaim (string atau array, misalnya groups); peran
+// tertinggi yang cocok dipakai. Tanpa kecocokan, DefaultRole dipakai; DefaultRole kosong
+// berarti login ditolak.
+type OIDCConfig struct {
+	Issuer        string `yaml:"issuer" env:"OIDC_ISSUER"`
+	ClientID      string `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret  string `yaml:"client_secret" env:"OIDC_CLIENT_SECRET" secret:"true"`
+	RedirectURL   string `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes        string `yaml:"scopes" env:"OIDC_SCOPES" default:"openid profile email"`
+	UsernameClaim string `yaml:"username_claim" env:"OIDC_USERNAME_CLAIM" default:"preferred_username"`
+	RoleClaim     string `yaml:"role_claim" env:"OIDC_ROLE_CLAIM" default:"groups"`
+	RoleMapping   string `yaml:"role_mapping" env:"OIDC_ROLE_MAPPING"`
+	DefaultRole   string `yaml:"default_role" env:"OIDC_DEFAULT_ROLE" default:"reader"`
+}
+
+// Enabled melaporkan apakah login OIDC diaktifkan
+func (o OIDCConfig) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// ParseRoleMapping memecah RoleMapping menjadi peta nilai klaim ke nama peran
+func (o OIDCConfig) ParseRoleMapping() (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, entry := range strings.Split(o.RoleMapping, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		value, role, ok := strings.Cut(entry, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if !ok || value == "" || role == "" {
+			return nil, fmt.Errorf("entri %q harus berformat nilai=peran", entry)
+		}
+		if !contains(validRoles, role) {
+			return nil, fmt.Errorf("peran %q tidak dikenal (pilihan: %s)", role, strings.Join(validRoles, ", "))
+		}
+		mapping[value] = role
+	}
+	return mapping, nil
+}
+
+// RateLimitConfig berisi pengaturan rate limit per klien (API key, pengguna, atau IP).
+// Search, Write, dan Read adalah kuota per kelas rute berformat "<jumlah>/<periode>",
+// misalnya "30/1m": bucket berisi 30 token yang terisi kembali merata selama satu menit.
+// Nilai "off" mematikan rate limit untuk kelas tersebut. Auth adalah kuota autentikasi
+// gagal (kredensial di header yang ditolak) per IP; setelah habis, request berkredensial
+// dari IP itu ditolak dengan 429 sebelum kredensialnya diperiksa. Store memory menyimpan
+// bucket di proses ini; postgres membagi bucket ke semua instance lewat tabel rate_limits.
+// TrustForwardedFor memakai alamat terakhir di X-Forwarded-For sebagai IP klien; aktifkan
+// hanya jika aplikasi berada di belakang satu reverse proxy tepercaya.
+type RateLimitConfig struct {
+	Enabled           bool   `yaml:"enabled" env:"RATE_LIMIT_ENABLED" default:"true"`
+	Store             string `yaml:"store" env:"RATE_LIMIT_STORE" default:"memory"`
+	Search            string `yaml:"search" env:"RATE_LIMIT_SEARCH" default:"30/1m"`
+	Write             string `yaml:"write" env:"RATE_LIMIT_WRITE" default:"60/1m"`
+	Read              string `yaml:"read" env:"RATE_LIMIT_READ" default:"300/1m"`
+	Auth              string `yaml:"auth" env:"RATE_LIMIT_AUTH" default:"20/1m"`
+	TrustForwardedFor bool   `yaml:"trust_forwarded_for" env:"RATE_LIMIT_TRUST_FORWARDED_FOR" default:"false"`
+}
+
+// CORSConfig berisi kebijakan CORS untuk aplikasi web di origin lain. AllowedOrigins
+// dipisah koma, berisi origin persis ("https://app.example.com"), subdomain wildcard
+// ("https://*.example.com", tidak termasuk example.com sendiri), atau "*" untuk semua
+// origin. Kosong berarti request lintas origin tidak diizinkan. AllowCredentials
+// mengizinkan cookie sesi ikut terkirim dan tidak bisa digabung dengan "*".
+type CORSConfig struct {
+	AllowedOrigins   string        `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" default:"false"`
+	AllowedHeaders   string        `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" default:"Content-Type, Authorization, X-Request-ID"`
+	ExposedHeaders   string        `yaml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" default:"X-Request-ID, RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" default:"10m"`
+}
+
+// Origins mengembalikan daftar origin yang diizinkan
+func (c CORSConfig) Origins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.AllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// validOriginPattern memeriksa satu pola origin: "*" atau skema://host[:port], dengan
+// host boleh diawali "*." untuk subdomain wildcard
+func validOriginPattern(pattern string) error {
+	if pattern == "*" {
+		return nil
+	}
+	u, err := url.Parse(pattern)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("origin %q harus berformat http(s)://host[:port]", pattern)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("origin %q tidak boleh memuat path, query, atau userinfo", pattern)
+	}
+	if host := u.Hostname(); strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+		return fmt.Errorf("origin %q: wildcard hanya boleh sebagai label pertama, misalnya https://*.example.com", pattern)
+	}
+	return nil
+}
+
+// ParseRate memecah kuota "<jumlah>/<periode>". Kuota "off" menghasilkan jumlah 0.
+func ParseRate(spec string) (int, time.Duration, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "off" {
+		return 0, 0, nil
+	}
+	count, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("kuota %q harus berformat <jumlah>/<periode>, misalnya 30/1m, atau off", spec)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("jumlah pada kuota %q harus bilangan bulat positif", spec)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return 0, 0, fmt.Errorf("periode pada kuota %q harus durasi positif, misalnya 1m", spec)
+	}
+	return n, d, nil
+}
+
+// minSigningKeyLength adalah panjang minimal rahasia HMAC, setara ukuran output SHA-256
+const minSigningKeyLength = 32
+
+// ParseSigningKeys memecah SigningKeys menjadi pasangan kid dan rahasia sesuai urutannya
+func (a AuthConfig) ParseSigningKeys() ([][2]string, error) {
+	var keys [][2]string
+	seen := make(map[string]bool)
+	for i, entry := range strings.Split(a.SigningKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// Entri yang salah format bisa saja seluruhnya rahasia, jadi hanya posisinya yang disebut
+		kid, secret, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("kunci ke-%d harus berformat kid:rahasia", i+1)
+		}
+		if len(secret) < minSigningKeyLength {
+			return nil, fmt.Errorf("rahasia kunci %q minimal %d karakter", kid, minSigningKeyLength)
+		}
+		if seen[kid] {
+			return nil, fmt.Errorf("kid %q dipakai lebih dari sekali", kid)
+		}
+		seen[kid] = true
+		keys = append(keys, [2]string{kid, secret})
+	}
+	return keys, nil
+}
+
+// App adalah konfigurasi aktif, diisi oleh Load saat startup.
+// Sebelum Load dipanggil, App berisi nilai bawaan.
+var App = Defaults()
+
+// Driver database yang didukung
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+var (
+	validDrivers    = []string{DriverPostgres, DriverSQLite}
+	validExporters  = []string{"none", "stdout", "otlp-file"}
+	validLogFormats = []string{"json", "text"}
+	validLogLevels  = []string{"debug", "info", "warn", "error"}
+	validRoles      = []string{"reader", "librarian", "admin"}
+	validRateStores = []string{"memory", "postgres"}
+	validSSLModes   = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	// validBackends memetakan driver ke backend pencarian yang bisa dipakai bersamanya;
+	// backend pertama dipakai jika SEARCH_BACKEND tidak diatur
+	validBackends = map[string][]string{
+		DriverPostgres: {"postgres", "like", "memory"},
+		DriverSQLite:   {"sqlite", "like", "memory"},
+	}
+)
+
+// applyDriverDefaults mengisi nilai bawaan yang bergantung pada DB_DRIVER, sehingga
+// DB_DRIVER=sqlite saja sudah cukup untuk menjalankan aplikasi
+func (c *Config) applyDriverDefaults() {
+	if backends, ok := validBackends[c.Database.Driver]; ok && c.Search.Backend == "" {
+		c.Search.Backend = backends[0]
+	}
+}
+
+// namedValue memasangkan nama variabel dengan nilainya agar urutan pesan validasi tetap
+type namedValue[T any] struct {
+	name  string
+	value T
+}
+
+// Validate memeriksa seluruh konfigurasi dan mengembalikan semua masalah sekaligus
+func (c *Config) Validate() error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if c.App.Port < 1 || c.App.Port > 65535 {
+		add("app: APP_PORT harus antara 1 dan 65535 (sekarang %d)", c.App.Port)
+	}
+	if c.App.ShutdownDelay < 0 {
+		add("app: SHUTDOWN_DELAY tidak boleh negatif")
+	}
+	if c.App.ShutdownTimeout <= 0 {
+		add("app: SHUTDOWN_TIMEOUT harus lebih dari 0")
+	}
+	if c.App.HealthTimeout <= 0 {
+		add("app: HEALTH_CHECK_TIMEOUT harus lebih dari 0")
+	}
+
+	db := c.Database
+	switch db.Driver {
+	case DriverPostgres:
+		validatePostgres(db, add)
+	case DriverSQLite:
+		if db.SQLitePath == "" {
+			add("database: SQLITE_PATH wajib diisi jika DB_DRIVER=sqlite")
+		}
+	default:
+		add("database: DB_DRIVER %q tidak dikenal (pilihan: %s)", db.Driver, strings.Join(validDrivers, ", "))
+	}
+	for _, d := range []namedValue[time.Duration]{
+		{"DB_CONNECT_DEADLINE", db.ConnectDeadline},
+		{"DB_CONNECT_INITIAL_BACKOFF", db.InitialBackoff},
+		{"DB_CONNECT_MAX_BACKOFF", db.MaxBackoff},
+		{"DB_CONN_MAX_LIFETIME", db.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", db.ConnMaxIdleTime},
+	} {
+		if d.value <= 0 {
+			add("database: %s harus lebih dari 0", d.name)
+		}
+	}
+	if db.InitialBackoff > db.MaxBackoff {
+		add("database: DB_CONNECT_INITIAL_BACKOFF tidak boleh lebih besar dari DB_CONNECT_MAX_BACKOFF")
+	}
+	if db.MaxOpenConns < 0 || db.MaxIdleConns < 0 {
+		add("database: DB_MAX_OPEN_CONNS dan DB_MAX_IDLE_CONNS tidak boleh negatif")
+	}
+	if db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+		add("database: DB_MAX_IDLE_CONNS (%d) tidak boleh melebihi DB_MAX_OPEN_CONNS (%d)", db.MaxIdleConns, db.MaxOpenConns)
+	}
+
+	s := c.Search
+	if backends, ok := validBackends[db.Driver]; ok && !contains(backends, s.Backend) {
+		add("search: SEARCH_BACKEND %q tidak bisa dipakai dengan DB_DRIVER=%s (pilihan: %s)",
+			s.Backend, db.Driver, strings.Join(backends, ", "))
+	}
+	if s.FuzzyThreshold <= 0 || s.FuzzyThreshold > 1 {
+		add("search: SEARCH_FUZZY_THRESHOLD harus di antara 0 (eksklusif) dan 1")
+	}
+	for _, boost := range []namedValue[float64]{
+		{"SEARCH_BOOST_TITLE", s.BoostTitle},
+		{"SEARCH_BOOST_AUTHOR", s.BoostAuthor},
+		{"SEARCH_BOOST_YEAR", s.BoostYear},
+	} {
+		if boost.value < 0 || boost.value > 1 {
+			add("search: %s harus di antara 0 dan 1", boost.name)
+		}
+	}
+	if s.RecencyWeight < 0 {
+		add("search: SEARCH_RECENCY_WEIGHT tidak boleh negatif")
+	}
+	if s.RecencyScale <= 0 {
+		add("search: SEARCH_RECENCY_SCALE harus lebih dari 0")
+	}
+
+	if c.Cache.BookSize < 0 {
+		add("cache: BOOK_CACHE_SIZE tidak boleh negatif")
+	}
+	if c.Cache.BookTTL < 0 || c.Cache.BookListTTL < 0 || c.Cache.StatsTTL < 0 {
+		add("cache: TTL cache tidak boleh negatif")
+	}
+
+	t := c.Tracing
+	if !contains(validExporters, t.Exporter) {
+		add("tracing: TRACE_EXPORTER %q tidak dikenal (pilihan: %s)", t.Exporter, strings.Join(validExporters, ", "))
+	}
+	if t.Exporter == "otlp-file" && t.File == "" {
+		add("tracing: TRACE_FILE wajib diisi jika TRACE_EXPORTER=otlp-file")
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		add("tracing: TRACE_SAMPLE_RATIO harus di antara 0 dan 1")
+	}
+
+	if !contains(validLogFormats, c.Log.Format) {
+		add("log: LOG_FORMAT %q tidak dikenal (pilihan: %s)", c.Log.Format, strings.Join(validLogFormats, ", "))
+	}
+	if !contains(validLogLevels, strings.ToLower(c.Log.Level)) {
+		add("log: LOG_LEVEL %q tidak dikenal (pilihan: %s)", c.Log.Level, strings.Join(validLogLevels, ", "))
+	}
+
+	if _, err := c.Auth.ParseSigningKeys(); err != nil {
+		add("auth: JWT_SIGNING_KEYS tidak valid: %v", err)
+	}
+	if c.Auth.AccessTTL <= 0 || c.Auth.RefreshTTL <= 0 {
+		add("auth: JWT_ACCESS_TTL dan JWT_REFRESH_TTL harus lebih dari 0")
+	}
+	if c.Auth.RefreshTTL < c.Auth.AccessTTL {
+		add("auth: JWT_REFRESH_TTL tidak boleh lebih pendek dari JWT_ACCESS_TTL")
+	}
+	if c.Auth.Issuer == "" {
+		add("auth: JWT_ISSUER wajib diisi")
+	}
+	if c.Auth.SessionTTL <= 0 {
+		add("auth: SESSION_TTL harus lebih dari 0")
+	}
+
+	if o := c.OIDC; o.Enabled() {
+		if u, err := url.Parse(o.Issuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			add("oidc: OIDC_ISSUER harus berupa URL http(s)")
+		}
+		if o.ClientID == "" {
+			add("oidc: OIDC_CLIENT_ID wajib diisi jika OIDC_ISSUER diisi")
+		}
+		if u, err := url.Parse(o.RedirectURL); err != nil || !u.IsAbs() {
+			add("oidc: OIDC_REDIRECT_URL wajib berupa URL absolut, misalnya http://localhost:8080/api/auth/oidc/callback")
+		}
+		if !contains(strings.Fields(o.Scopes), "openid") {
+			add("oidc: OIDC_SCOPES harus memuat openid")
+		}
+		if o.UsernameClaim == "" {
+			add("oidc: OIDC_USERNAME_CLAIM wajib diisi")
+		}
+		if _, err := o.ParseRoleMapping(); err != nil {
+			add("oidc: OIDC_ROLE_MAPPING tidak valid: %v", err)
+		}
+		if o.DefaultRole != "" && !contains(validRoles, o.DefaultRole) {
+			add("oidc: OIDC_DEFAULT_ROLE %q tidak dikenal (pilihan: %s, atau kosong untuk menolak login)",
+				o.DefaultRole, strings.Join(validRoles, ", "))
+		}
+	}
+
+	if rl := c.RateLimit; rl.Enabled {
+		if !contains(validRateStores, rl.Store) {
+			add("rate_limit: RATE_LIMIT_STORE %q tidak dikenal (pilihan: %s)", rl.Store, strings.Join(validRateStores, ", "))
+		} else if rl.Store == "postgres" && db.Driver != DriverPostgres {
+			add("rate_limit: RATE_LIMIT_STORE=postgres membutuhkan DB_DRIVER=postgres")
+		}
+		for _, q := range []namedValue[string]{
+			{"RATE_LIMIT_SEARCH", rl.Search},
+			{"RATE_LIMIT_WRITE", rl.Write},
+			{"RATE_LIMIT_READ", rl.Read},
+			{"RATE_LIMIT_AUTH", rl.Auth},
+		} {
+			if _, _, err := ParseRate(q.value); err != nil {
+				add("rate_limit: %s tidak valid: %v", q.name, err)
+			}
+		}
+	}
+
+	cors := c.CORS
+	for _, origin := range cors.Origins() {
+		if err := validOriginPattern(origin); err != nil {
+			add("cors: CORS_ALLOWED_ORIGINS tidak valid: %v", err)
+		} else if origin == "*" && cors.AllowCredentials {
+			add("cors: CORS_ALLOWED_ORIGINS=* tidak bisa dipakai bersama CORS_ALLOW_CREDENTIALS=true; sebutkan origin-nya")
+		}
+	}
+	if cors.MaxAge < 0 {
+		add("cors: CORS_MAX_AGE tidak boleh negatif")
+	}
+
+	return errors.Join(problems...)
+}
+
+// validatePostgres memeriksa pengaturan yang hanya berlaku untuk driver postgres
+func validatePostgres(db DatabaseConfig, add func(format string, args ...interface{})) {
+	if db.URL != "" {
+		u, err := url.Parse(db.URL)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			add("database: DATABASE_URL harus berupa URL postgres://")
+		} else if strings.TrimPrefix(u.Path, "/") == "" {
+			add("database: DATABASE_URL harus menyertakan nama database")
+		}
+	} else {
+		if db.Name == "" {
+			add("database: DB_NAME atau DATABASE_URL wajib diisi")
+		}
+		if db.Host == "" {
+			add("database: DB_HOST wajib diisi")
+		}
+		if db.Port < 1 || db.Port > 65535 {
+			add("database: DB_PORT harus antara 1 dan 65535 (sekarang %d)", db.Port)
+		}
+	}
+	if db.AdminURL != "" {
+		u, err := url.Parse(db.AdminURL)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			add("database: DB_ADMIN_URL harus berupa URL postgres://")
+		}
+	}
+	if !contains(validSSLModes, db.SSLMode) {
+		add("database: DB_SSLMODE %q tidak valid (pilihan: %s)", db.SSLMode, strings.Join(validSSLModes, ", "))
+	}
+	for _, file := range []namedValue[string]{
+		{"DB_SSLROOTCERT", db.SSLRootCert},
+		{"DB_SSLCERT", db.SSLCert},
+		{"DB_SSLKEY", db.SSLKey},
+	} {
+		if file.value == "" {
+			continue
+		}
+		if _, err := os.Stat(file.value); err != nil {
+			add("database: %s tidak dapat dibaca: %v", file.name, err)
+		}
+	}
+	if (db.SSLCert == "") != (db.SSLKey == "") {
+		add("database: DB_SSLCERT dan DB_SSLKEY harus diisi bersamaan")
+	}
+}
+
+// ConnString mengembalikan connection string ke database dbName.
+// dbName kosong berarti memakai database aplikasi.
+func (d DatabaseConfig) ConnString(dbName string) string {
+	if d.URL != "" {
+		u, err := url.Parse(d.URL)
+		if err != nil {
+			return d.URL
+		}
+		if dbName != "" {
+			u.Path = "/" + dbName
+		}
+		q := u.Query()
+		for key, value := range d.sslParams() {
+			if q.Get(key) == "" {
+				q.Set(key, value)
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	if dbName == "" {
+		dbName = d.Name
+	}
+	params := []string{
+		"host=" + quoteConnValue(d.Host),
+		fmt.Sprintf("port=%d", d.Port),
+		"user=" + quoteConnValue(d.User),
+		"password=" + quoteConnValue(d.Password),
+		"dbname=" + quoteConnValue(dbName),
+	}
+	for _, key := range []string{"sslmode", "sslrootcert", "sslcert", "sslkey"} {
+		if value, ok := d.sslParams()[key]; ok {
+			params = append(params, key+"="+quoteConnValue(value))
+		}
+	}
+	return strings.Join(params, " ")
+}
+
+// DatabaseName mengembalikan nama database aplikasi, termasuk jika diatur lewat DATABASE_URL
+func (d DatabaseConfig) DatabaseName() string {
+	if d.URL != "" {
+		if u, err := url.Parse(d.URL); err == nil {
+			return strings.TrimPrefix(u.Path, "/")
+		}
+	}
+	return d.Name
+}
+
+// Username mengembalikan role database aplikasi, termasuk jika diatur lewat DATABASE_URL
+func (d DatabaseConfig) Username() string {
+	if d.URL != "" {
+		if u, err := url.Parse(d.URL); err == nil && u.User != nil {
+			return u.User.Username()
+		}
+		return ""
+	}
+	return d.User
+}
+
+// userPassword mengembalikan password role database aplikasi
+func (d DatabaseConfig) userPassword() string {
+	if d.URL != "" {
+		if u, err := url.Parse(d.URL); err == nil && u.User != nil {
+			password, _ := u.User.Password()
+			return password
+		}
+		return ""
+	}
+	return d.Password
+}
+
+// AdminConnString mengembalikan connection string administratif ke database dbName.
+// Tanpa DB_ADMIN_URL, kredensial aplikasi yang dipakai.
+func (d DatabaseConfig) AdminConnString(dbName string) string {
+	if d.AdminURL == "" {
+		return d.ConnString(dbName)
+	}
+	return DatabaseConfig{URL: d.AdminURL, SSLMode: d.SSLMode, SSLRootCert: d.SSLRootCert,
+		SSLCert: d.SSLCert, SSLKey: d.SSLKey}.ConnString(dbName)
+}
+
+func (d DatabaseConfig) sslParams() map[string]string {
+	params := map[string]string{"sslmode": d.SSLMode}
+	if d.SSLRootCert != "" {
+		params["sslrootcert"] = d.SSLRootCert
+	}
+	if d.SSLCert != "" {
+		params["sslcert"] = d.SSLCert
+	}
+	if d.SSLKey != "" {
+		params["sslkey"] = d.SSLKey
+	}
+	return params
+}
+
+// quoteConnValue mengutip nilai connection string key=value sesuai aturan libpq
+func quoteConnValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// Entri kunci yang salah format tidak boleh muncul, sebagian pun, di pesan error
+func TestParseSigningKeysDoesNotEchoSecret(t *testing.T) {
+	const secret = "s3cr3t-yang-panjangnya-lebih-dari-32-karakter"
+	for _, keys := range []string{
+		secret,
+		":" + secret,
+		"kid-1:0123456789abcdef0123456789abcdef," + secret,
+	} {
+		_, err := AuthConfig{SigningKeys: keys}.ParseSigningKeys()
+		if err == nil {
+			t.Errorf("ParseSigningKeys(%q) seharusnya gagal", keys)
+			continue
+		}
+		if strings.Contains(err.Error(), secret[:3]) {
+			t.Errorf("error membocorkan rahasia: %v", err)
+		}
+	}
+}
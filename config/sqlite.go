@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"crud-buku-go/migrations"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteConnString mengembalikan DSN SQLite untuk file dbPath. WAL dan busy_timeout
+// dipasang agar pembaca tidak terblokir penulis dan penulisan bersamaan menunggu
+// alih-alih langsung gagal dengan SQLITE_BUSY.
+func SQLiteConnString(dbPath string) string {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Set("_time_format", "sqlite")
+	return "file:" + dbPath + "?" + params.Encode()
+}
+
+// connectSQLite membuka database SQLite di SQLITE_PATH dan menerapkan migrasinya
+func connectSQLite() {
+	dbPath := App.Database.SQLitePath
+	connStr := SQLiteConnString(dbPath)
+
+	database, err := sql.Open("sqlite", connStr)
+	if err != nil {
+		log.Fatalf("Gagal membuka database SQLite '%s': %v", dbPath, err)
+	}
+	configurePool(database)
+
+	ctx, cancel := context.WithTimeout(context.Background(), App.Database.ConnectDeadline)
+	defer cancel()
+	if err := database.PingContext(ctx); err != nil {
+		log.Fatalf("Gagal membuka database SQLite '%s': %v", dbPath, err)
+	}
+	if err := MigrateSQLite(ctx, database); err != nil {
+		log.Fatalf("Gagal menjalankan migrasi SQLite: %v", err)
+	}
+
+	DB = database
+	DSN = connStr
+	log.Printf("Berhasil membuka database SQLite '%s'!", dbPath)
+}
+
+// MigrateSQLite menerapkan migrasi SQLite yang belum dijalankan, berurutan
+// berdasarkan nama file. Versi yang sudah diterapkan dicatat di schema_migrations.
+func MigrateSQLite(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+
+	files, err := sqliteMigrationFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		version := migrationVersion(file)
+		if err := applySQLiteMigration(ctx, db, version, file); err != nil {
+			return fmt.Errorf("migrasi %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// sqliteMigrationFiles mengembalikan file migrasi SQLite yang disertakan, terurut berdasarkan nama
+func sqliteMigrationFiles() ([]string, error) {
+	files, err := fs.Glob(migrations.SQLite, "sqlite/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// migrationVersion mengubah "sqlite/001_create_books.sql" menjadi "001_create_books"
+func migrationVersion(file string) string {
+	return strings.TrimSuffix(path.Base(file), ".sql")
+}
+
+func applySQLiteMigration(ctx context.Context, db *sql.DB, version, file string) error {
+	var applied bool
+	err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)", version).Scan(&applied)
+	if err != nil || applied {
+		return err
+	}
+
+	script, err := migrations.SQLite.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("Migrasi SQLite %s diterapkan.", version)
+	return nil
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/utils"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetConfigHandler menghandle request untuk melihat konfigurasi aktif
+// @Summary Dump konfigurasi aktif
+// @Description Mengembalikan konfigurasi aplikasi yang sedang berlaku dengan nilai rahasia disamarkan.
+// @Description Membutuhkan izin config:read (peran admin).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Konfigurasi aktif (tersamarkan)"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin config:read tidak dimiliki"
+// @Security BearerAuth
+// @Router /admin/config [get]
+func GetConfigHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, config.App.Redacted())
+}
+
+// GetPermissionsHandler menghandle request untuk melihat matriks izin
+// @Summary Matriks izin
+// @Description Mengembalikan izin setiap peran (reader, librarian, admin) dan izin yang dibutuhkan setiap rute.
+// @Description Membutuhkan izin users:manage (peran admin).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} auth.PermissionMatrix "Matriks izin"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin users:manage tidak dimiliki"
+// @Security BearerAuth
+// @Router /admin/permissions [get]
+func GetPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, auth.Matrix())
+}
+
+// ListUsersHandler menghandle request untuk melihat semua pengguna
+// @Summary Daftar pengguna
+// @Description Mengembalikan semua pengguna beserta perannya. Membutuhkan izin users:manage (peran admin).
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.User "Daftar pengguna"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin users:manage tidak dimiliki"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Security BearerAuth
+// @Router /admin/users [get]
+func ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	users, err := models.ListUsers(r.Context())
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, users)
+}
+
+// @Description Peran baru pengguna
+// UpdateRoleRequest adalah payload untuk mengganti peran pengguna
+type UpdateRoleRequest struct {
+	Role string `json:"role" example:"librarian" enums:"reader,librarian,admin"`
+}
+
+// UpdateUserRoleHandler menghandle request untuk mengganti peran pengguna
+// @Summary Mengganti peran pengguna
+// @Description Mengganti peran pengguna menjadi reader, librarian, atau admin. Peran baru berlaku
+// @Description setelah pengguna login ulang atau menukar token refresh. Membutuhkan izin users:manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Pengguna"
+// @Param role body UpdateRoleRequest true "Peran baru"
+// @Success 200 {object} models.User "Pengguna dengan peran baru"
+// @Failure 400 {object} utils.Problem "ID pengguna, payload, atau peran tidak valid"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin users:manage tidak dimiliki"
+// @Failure 404 {object} utils.Problem "Pengguna tidak ditemukan"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [put]
+func UpdateUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID pengguna tidak valid")
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Payload request tidak valid")
+		return
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Admin tidak bisa menurunkan perannya sendiri agar selalu ada admin yang tersisa
+	if principal, ok := auth.FromContext(r.Context()); ok && principal.UserID == id && role != auth.RoleAdmin {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Tidak bisa menurunkan peran akun sendiri")
+		return
+	}
+
+	if err := models.UpdateUserRole(r.Context(), id, string(role)); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	user, err := models.GetUserByID(r.Context(), id)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	slog.InfoContext(r.Context(), "Peran pengguna diganti", "user_id", id, "role", role)
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
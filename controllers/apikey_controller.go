@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"crud-buku-go/auth"
+	"crud-buku-go/models"
+	"crud-buku-go/utils"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// @Description Data API key baru
+// CreateAPIKeyRequest adalah payload untuk membuat API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" example:"import-katalog-harian"`
+	Scopes    []string   `json:"scopes" example:"books:read,books:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// @Description API key yang baru dibuat. Nilai key hanya ditampilkan sekali.
+// CreatedAPIKey adalah response pembuatan API key
+type CreatedAPIKey struct {
+	Key string `json:"key"`
+	models.APIKey
+}
+
+// maxAPIKeyNameLength sama dengan panjang kolom name di tabel api_keys
+const maxAPIKeyNameLength = 100
+
+// CreateAPIKeyHandler menghandle request pembuatan API key
+// @Summary Membuat API key
+// @Description Membuat API key untuk klien mesin dengan scope books:read, books:write, dan/atau import.
+// @Description Key dikirim di header "Authorization: ApiKey <key>" dan hanya ditampilkan sekali di response ini;
+// @Description server hanya menyimpan hash-nya. Membutuhkan izin apikeys:manage (peran admin).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body CreateAPIKeyRequest true "Nama, scope, dan waktu kedaluwarsa (opsional)"
+// @Success 201 {object} CreatedAPIKey "API key berhasil dibuat"
+// @Failure 400 {object} utils.Problem "Payload, scope, atau waktu kedaluwarsa tidak valid"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin apikeys:manage tidak dimiliki"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Security BearerAuth
+// @Router /admin/api-keys [post]
+func CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Payload request tidak valid")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxAPIKeyNameLength {
+		utils.RespondWithError(w, r, http.StatusBadRequest,
+			"Nama API key wajib diisi dan maksimal "+strconv.Itoa(maxAPIKeyNameLength)+" karakter")
+		return
+	}
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "expires_at harus di masa depan")
+		return
+	}
+
+	key, keyID, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	apiKey := models.APIKey{Name: req.Name, KeyID: keyID, Hash: hash, ExpiresAt: req.ExpiresAt}
+	for _, scope := range scopes {
+		apiKey.Scopes = append(apiKey.Scopes, string(scope))
+	}
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		apiKey.CreatedBy = principal.UserID
+	}
+	if err := models.CreateAPIKey(r.Context(), &apiKey); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "API key dibuat", "api_key_id", apiKey.ID, "name", apiKey.Name, "scopes", apiKey.Scopes)
+	w.Header().Set("Cache-Control", "no-store")
+	utils.RespondWithJSON(w, http.StatusCreated, CreatedAPIKey{Key: key, APIKey: apiKey})
+}
+
+// ListAPIKeysHandler menghandle request daftar API key
+// @Summary Daftar API key
+// @Description Mengembalikan semua API key beserta scope, waktu kedaluwarsa, pemakaian terakhir, dan
+// @Description status pencabutan, tanpa nilai key maupun hash-nya. Membutuhkan izin apikeys:manage.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.APIKey "Daftar API key"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin apikeys:manage tidak dimiliki"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Security BearerAuth
+// @Router /admin/api-keys [get]
+func ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := models.ListAPIKeys(r.Context())
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, keys)
+}
+
+// RevokeAPIKeyHandler menghandle request pencabutan API key
+// @Summary Mencabut API key
+// @Description Mencabut API key sehingga langsung tidak bisa dipakai lagi. Key yang dicabut tetap
+// @Description tampil di daftar untuk keperluan audit. Membutuhkan izin apikeys:manage.
+// @Tags admin
+// @Produce json
+// @Param id path int true "ID API key"
+// @Success 200 {object} map[string]string "API key berhasil dicabut"
+// @Failure 400 {object} utils.Problem "ID API key tidak valid"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin apikeys:manage tidak dimiliki"
+// @Failure 404 {object} utils.Problem "API key tidak ditemukan atau sudah dicabut"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Security BearerAuth
+// @Router /admin/api-keys/{id} [delete]
+func RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID API key tidak valid")
+		return
+	}
+	if err := models.RevokeAPIKey(r.Context(), id); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	slog.InfoContext(r.Context(), "API key dicabut", "api_key_id", id)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "API key berhasil dicabut"})
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"crud-buku-go/auth"
+	"crud-buku-go/models"
+	"crud-buku-go/utils"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// @Description Kredensial login
+// LoginRequest adalah payload untuk endpoint login
+type LoginRequest struct {
+	Username string `json:"username" example:"admin"`
+	Password string `json:"password" example:"rahasia123"`
+}
+
+// @Description Token refresh yang ditukar dengan pasangan token baru
+// RefreshRequest adalah payload untuk endpoint refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginHandler menghandle request login
+// @Summary Login
+// @Description Memeriksa username dan password, lalu menerbitkan token akses (Bearer) dan token refresh.
+// @Description Token akses dipakai di header Authorization; izinnya mengikuti peran pengguna (reader, librarian, admin).
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Username dan password"
+// @Success 200 {object} auth.TokenPair "Token berhasil diterbitkan"
+// @Failure 400 {object} utils.Problem "Payload request tidak valid"
+// @Failure 401 {object} utils.Problem "Username atau password salah"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Router /auth/login [post]
+func LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username == "" || req.Password == "" {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Payload request tidak valid")
+		return
+	}
+
+	user, err := models.GetUserByUsername(r.Context(), strings.ToLower(strings.TrimSpace(req.Username)))
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		respondWithError(w, r, err)
+		return
+	}
+	// CheckPassword tetap dijalankan untuk pengguna yang tidak ada agar waktunya sama
+	if !auth.CheckPassword(user.PasswordHash, req.Password) {
+		slog.WarnContext(r.Context(), "Login gagal", "username", req.Username)
+		utils.RespondWithError(w, r, http.StatusUnauthorized, "Username atau password salah")
+		return
+	}
+
+	respondWithTokens(w, r, user)
+}
+
+// RefreshHandler menghandle request penukaran token refresh
+// @Summary Refresh token
+// @Description Menukar token refresh yang masih berlaku dengan pasangan token akses dan refresh baru.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body RefreshRequest true "Token refresh"
+// @Success 200 {object} auth.TokenPair "Token berhasil diterbitkan"
+// @Failure 400 {object} utils.Problem "Payload request tidak valid"
+// @Failure 401 {object} utils.Problem "Token refresh tidak valid atau kedaluwarsa"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Router /auth/refresh [post]
+func RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Payload request tidak valid")
+		return
+	}
+
+	principal, err := auth.ParseToken(req.RefreshToken, auth.TokenRefresh)
+	if err != nil {
+		utils.RespondWithError(w, r, http.StatusUnauthorized, "Token refresh tidak valid atau kedaluwarsa")
+		return
+	}
+
+	// Pengguna yang sudah dihapus tidak bisa memperpanjang sesinya
+	user, err := models.GetUserByID(r.Context(), principal.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			utils.RespondWithError(w, r, http.StatusUnauthorized, "Token refresh tidak valid atau kedaluwarsa")
+		} else {
+			respondWithError(w, r, err)
+		}
+		return
+	}
+
+	respondWithTokens(w, r, user)
+}
+
+// respondWithTokens menerbitkan dan mengirim pasangan token untuk user
+func respondWithTokens(w http.ResponseWriter, r *http.Request, user models.User) {
+	role, err := auth.ParseRole(user.Role)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Peran pengguna di database tidak dikenal", "user_id", user.ID, "error", err)
+		utils.RespondWithError(w, r, http.StatusForbidden, "Peran pengguna tidak dikenal")
+		return
+	}
+	tokens, err := auth.IssueTokens(user.ID, user.Username, role)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	slog.InfoContext(r.Context(), "Token diterbitkan", "user_id", user.ID, "username", user.Username, "role", role)
+	utils.RespondWithJSON(w, http.StatusOK, tokens)
+}
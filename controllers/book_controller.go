@@ -1,11 +1,18 @@
 package controllers
 
 import (
+	"context"
+	"crud-buku-go/metrics"
 	"crud-buku-go/models"
+	"crud-buku-go/search"
+	"crud-buku-go/searchql"
 	"crud-buku-go/utils"
-	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -19,9 +26,9 @@ import (
 // @Success 200 {array} models.Book "Daftar semua buku"
 // @Router /books [get]
 func GetBooksHandler(w http.ResponseWriter, r *http.Request) {
-	books, err := models.GetAllBooks()
+	books, err := models.GetAllBooks(r.Context())
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, books)
@@ -35,25 +42,21 @@ func GetBooksHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "ID Buku"
 // @Success 200 {object} models.Book "Detail buku"
-// @Failure 400 {object} map[string]string "ID buku tidak valid"
-// @Failure 404 {object} map[string]string "Buku tidak ditemukan"
-// @Failure 500 {object} map[string]string "Kesalahan server internal"
+// @Failure 400 {object} utils.Problem "ID buku tidak valid"
+// @Failure 404 {object} utils.Problem "Buku tidak ditemukan"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
 // @Router /books/{id} [get]
 func GetBookHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "ID buku tidak valid")
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID buku tidak valid")
 		return
 	}
 
-	book, err := models.GetBookByID(id)
+	book, err := models.GetBookByID(r.Context(), id)
 	if err != nil {
-		if err.Error() == "buku tidak ditemukan" {
-			utils.RespondWithError(w, http.StatusNotFound, err.Error())
-		} else {
-			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, book)
@@ -61,33 +64,35 @@ func GetBookHandler(w http.ResponseWriter, r *http.Request) {
 
 // CreateBookHandler menghandle request untuk membuat buku baru
 // @Summary Membuat buku baru
-// @Description Menambahkan buku baru ke database.
+// @Description Menambahkan buku baru ke database. Spasi di awal dan akhir judul dan penulis dibuang;
+// @Description judul dan penulis maksimal 255 karakter, tahun antara 1000 dan tahun berjalan.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param book body models.Book true "Data buku baru"
 // @Success 201 {object} models.Book "Buku berhasil dibuat"
-// @Failure 400 {object} map[string]string "Payload request tidak valid atau data buku tidak lengkap"
-// @Failure 500 {object} map[string]string "Kesalahan server internal"
+// @Failure 400 {object} utils.Problem "Payload request bukan JSON yang valid"
+// @Failure 413 {object} utils.Problem "Body request terlalu besar"
+// @Failure 422 {object} utils.Problem{errors=[]models.FieldError} "Field tidak dikenal, tipe salah, atau data buku tidak valid"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books [post]
 func CreateBookHandler(w http.ResponseWriter, r *http.Request) {
 	var book models.Book
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&book); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Payload request tidak valid")
+	if err := decodeJSON(w, r, &book); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
-	defer r.Body.Close()
-
-	// Validasi dasar
-	if book.Title == "" || book.Author == "" || book.Year == 0 {
-		utils.RespondWithError(w, http.StatusBadRequest, "Judul, Penulis, dan Tahun tidak boleh kosong")
+	book.Normalize()
+	if err := book.Validate(); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
-
-	if err := models.CreateBook(&book); err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	if err := models.CreateBook(r.Context(), &book); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusCreated, book)
@@ -102,45 +107,42 @@ func CreateBookHandler(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID Buku"
 // @Param book body models.Book true "Data buku yang diperbarui"
 // @Success 200 {object} models.Book "Buku berhasil diperbarui"
-// @Failure 400 {object} map[string]string "ID buku tidak valid atau payload request tidak valid"
-// @Failure 404 {object} map[string]string "Buku tidak ditemukan untuk diperbarui"
-// @Failure 500 {object} map[string]string "Kesalahan server internal"
+// @Failure 400 {object} utils.Problem "ID buku tidak valid atau payload request tidak valid"
+// @Failure 413 {object} utils.Problem "Body request terlalu besar"
+// @Failure 422 {object} utils.Problem{errors=[]models.FieldError} "Field tidak dikenal, tipe salah, atau data buku tidak valid"
+// @Failure 404 {object} utils.Problem "Buku tidak ditemukan untuk diperbarui"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/{id} [put]
 func UpdateBookHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "ID buku tidak valid")
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID buku tidak valid")
 		return
 	}
 
 	var book models.Book
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&book); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Payload request tidak valid")
+	if err := decodeJSON(w, r, &book); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
-	defer r.Body.Close()
-
-	// Validasi dasar
-	if book.Title == "" || book.Author == "" || book.Year == 0 {
-		utils.RespondWithError(w, http.StatusBadRequest, "Judul, Penulis, dan Tahun tidak boleh kosong")
+	book.Normalize()
+	if err := book.Validate(); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
-
-	if err := models.UpdateBook(id, &book); err != nil {
-		if err.Error() == "buku tidak ditemukan untuk diperbarui" {
-			utils.RespondWithError(w, http.StatusNotFound, err.Error())
-		} else {
-			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
+	if err := models.UpdateBook(r.Context(), id, &book); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 	// Ambil data buku yang sudah terupdate untuk response
-	updatedBook, err := models.GetBookByID(id)
+	updatedBook, err := models.GetBookByID(r.Context(), id)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Gagal mengambil data buku setelah update")
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, updatedBook)
@@ -154,29 +156,35 @@ func UpdateBookHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "ID Buku"
 // @Success 200 {object} map[string]string "Pesan sukses penghapusan"
-// @Failure 400 {object} map[string]string "ID buku tidak valid"
-// @Failure 404 {object} map[string]string "Buku tidak ditemukan untuk dihapus"
-// @Failure 500 {object} map[string]string "Kesalahan server internal"
+// @Failure 400 {object} utils.Problem "ID buku tidak valid"
+// @Failure 404 {object} utils.Problem "Buku tidak ditemukan untuk dihapus"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/{id} [delete]
 func DeleteBookHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "ID buku tidak valid")
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID buku tidak valid")
 		return
 	}
 
-	if err := models.DeleteBook(id); err != nil {
-		if err.Error() == "buku tidak ditemukan untuk dihapus" {
-			utils.RespondWithError(w, http.StatusNotFound, err.Error())
-		} else {
-			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
+	if err := models.DeleteBook(r.Context(), id); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Buku berhasil dihapus"})
 }
 
+// bookPatch adalah payload PATCH; field nil berarti tidak diubah
+type bookPatch struct {
+	Title  *string `json:"title"`
+	Author *string `json:"author"`
+	Year   *int    `json:"year"`
+}
+
 // PatchBookHandler menghandle request untuk memperbarui sebagian data buku
 // @Summary Memperbarui sebagian data buku
 // @Description Memperbarui sebagian data buku (judul, penulis, atau tahun) berdasarkan ID.
@@ -186,50 +194,49 @@ func DeleteBookHandler(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID Buku"
 // @Param book body models.Book true "Data buku yang akan diperbarui (hanya field yang ingin diubah)"
 // @Success 200 {object} models.Book "Buku berhasil diperbarui (sebagian)"
-// @Failure 400 {object} map[string]string "ID buku tidak valid atau payload request tidak valid"
-// @Failure 404 {object} map[string]string "Buku tidak ditemukan untuk diperbarui"
-// @Failure 500 {object} map[string]string "Kesalahan server internal"
+// @Failure 400 {object} utils.Problem "ID buku tidak valid atau payload request tidak valid"
+// @Failure 413 {object} utils.Problem "Body request terlalu besar"
+// @Failure 422 {object} utils.Problem{errors=[]models.FieldError} "Field tidak dikenal, tipe salah, atau data buku hasil gabungan tidak valid"
+// @Failure 404 {object} utils.Problem "Buku tidak ditemukan untuk diperbarui"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/{id} [patch]
 func PatchBookHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "ID buku tidak valid")
+		utils.RespondWithError(w, r, http.StatusBadRequest, "ID buku tidak valid")
 		return
 	}
 
 	// Ambil buku yang ada dari database
-	existingBook, err := models.GetBookByID(id)
+	existingBook, err := models.GetBookByID(r.Context(), id)
 	if err != nil {
-		if err.Error() == "buku tidak ditemukan" { // Sesuai dengan error dari GetBookByID
-			utils.RespondWithError(w, http.StatusNotFound, "Buku tidak ditemukan untuk diperbarui")
-		} else {
-			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
+		respondWithError(w, r, err)
 		return
 	}
 
-	var payloadBook models.Book // Untuk menampung data dari request
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&payloadBook); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Payload request tidak valid")
+	var payload bookPatch // Untuk menampung data dari request
+	if err := decodeJSON(w, r, &payload); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
-	defer r.Body.Close()
 
-	// Terapkan pembaruan parsial
-	// Hanya perbarui field jika ada di payload dan tidak kosong/nol
+	// Terapkan pembaruan parsial: hanya field yang ada di payload yang diubah,
+	// lalu buku hasil gabungan divalidasi seperti pada PUT
 	updated := false
-	if payloadBook.Title != "" {
-		existingBook.Title = payloadBook.Title
+	if payload.Title != nil {
+		existingBook.Title = *payload.Title
 		updated = true
 	}
-	if payloadBook.Author != "" {
-		existingBook.Author = payloadBook.Author
+	if payload.Author != nil {
+		existingBook.Author = *payload.Author
 		updated = true
 	}
-	if payloadBook.Year != 0 { // Asumsi tahun tidak boleh 0 jika diisi, konsisten dengan validasi lain
-		existingBook.Year = payloadBook.Year
+	if payload.Year != nil {
+		existingBook.Year = *payload.Year
 		updated = true
 	}
 
@@ -238,18 +245,23 @@ func PatchBookHandler(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithJSON(w, http.StatusOK, existingBook)
 		return
 	}
+	existingBook.Normalize()
+	if err := existingBook.Validate(); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
 
 	// Perbarui buku di database menggunakan fungsi UpdateBook yang ada
 	// Asumsi: models.UpdateBook akan memperbarui semua field dari objek existingBook yang diteruskan.
-	if err := models.UpdateBook(id, &existingBook); err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Gagal memperbarui buku: "+err.Error())
+	if err := models.UpdateBook(r.Context(), id, &existingBook); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
 	// Ambil data buku yang sudah terupdate untuk response (untuk memastikan konsistensi)
-	finalUpdatedBook, err := models.GetBookByID(id)
+	finalUpdatedBook, err := models.GetBookByID(r.Context(), id)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Gagal mengambil data buku setelah update")
+		respondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, finalUpdatedBook)
@@ -257,26 +269,217 @@ func PatchBookHandler(w http.ResponseWriter, r *http.Request) {
 
 // SearchBooksHandler handles book search requests
 // @Summary Search books
-// @Description Search books by title, author, or year
+// @Description Search books by title, author, or year using the configured search backend (SEARCH_BACKEND:
+// @Description postgres, like, memory or sqlite); the backend that served the results is reported in the response.
+// @Description When the exact search finds nothing (or fuzzy=true),
+// @Description a typo-tolerant trigram search is used and a "did you mean" suggestion is returned on zero hits.
+// @Description Fuzzy search and suggestions need PostgreSQL and are unavailable with DB_DRIVER=sqlite.
+// @Description The query also accepts a fielded syntax, e.g. `author:"Dee Lestari" year:2005..2012 -title:filosofi`,
+// @Description with field prefixes (title, author, year), year ranges, quoted phrases, negation (-), OR and parentheses.
+// @Description Fielded queries run on the configured backend, are ordered by title and do not accept fuzzy or explain.
 // @Tags books
 // @Produce json
-// @Param q query string true "Search query (can be title, author, or year)" 
-// @Success 200 {array} models.Book "List of matching books"
-// @Failure 400 {object} map[string]string "Search query is required"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Param q query string true "Search query (plain text or fielded query syntax)"
+// @Param fuzzy query bool false "Force trigram similarity search"
+// @Param threshold query number false "Trigram similarity threshold between 0 and 1"
+// @Param explain query bool false "Include each hit's score breakdown"
+// @Success 200 {object} models.SearchResponse "Matching books"
+// @Failure 400 {object} utils.Problem "Search query is required, malformed, threshold is invalid, fuzzy is unavailable, or fuzzy/explain was combined with a fielded query"
+// @Failure 429 {object} utils.Problem{retry_after=int} "Search rate limit exceeded; see Retry-After"
+// @Failure 500 {object} utils.Problem "Search backend failed"
 // @Router /books/search [get]
 func SearchBooksHandler(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
+	params := r.URL.Query()
+	query := params.Get("q")
 	if query == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Search query parameter 'q' is required")
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Search query parameter 'q' is required")
 		return
 	}
 
-	books, err := models.SearchBooks(query)
+	parsedQuery, err := searchql.Parse(query)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		var syntaxErr *searchql.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			respondWithSyntaxError(w, r, syntaxErr)
+			return
+		}
+		utils.RespondWithError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.RespondWithJSON(w, http.StatusOK, books)
-}
\ No newline at end of file
+	fuzzy := false
+	if raw := params.Get("fuzzy"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameter 'fuzzy' must be a boolean")
+			return
+		}
+		fuzzy = parsed
+	}
+	if fuzzy && !models.TrigramAvailable() {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Fuzzy search requires the PostgreSQL database driver")
+		return
+	}
+
+	explain := false
+	if raw := params.Get("explain"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameter 'explain' must be a boolean")
+			return
+		}
+		explain = parsed
+	}
+
+	threshold := models.FuzzyThreshold()
+	if raw := params.Get("threshold"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameter 'threshold' must be a number between 0 and 1")
+			return
+		}
+		threshold = parsed
+	}
+
+	searcher := search.Active
+
+	// Query berfield tidak memiliki skor relevansi, jadi fuzzy dan explain tidak berlaku
+	if !parsedQuery.Plain() {
+		if fuzzy || explain {
+			utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameters 'fuzzy' and 'explain' are not supported for fielded queries")
+			return
+		}
+		results, err := search.RunQuery(r.Context(), searcher, parsedQuery, search.Options{})
+		if err != nil {
+			respondWithSearchError(w, r, err)
+			return
+		}
+		response := models.SearchResponse{Query: query, Mode: "query", Backend: searcher.Name(), Results: results}
+		metrics.ObserveSearch(response.Backend, response.Mode, len(response.Results))
+		utils.RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
+	response := models.SearchResponse{Query: query, Mode: "exact", Backend: searcher.Name(), Results: []models.SearchResult{}}
+
+	if !fuzzy {
+		opts := search.Options{Relevance: models.LoadRelevanceConfig(), Explain: explain}
+		results, err := search.Run(r.Context(), searcher, query, opts)
+		if err != nil {
+			respondWithSearchError(w, r, err)
+			return
+		}
+		response.Results = append(response.Results, results...)
+	}
+
+	// Tidak ada hasil yang persis sama, coba pencarian trigram yang toleran typo
+	if len(response.Results) == 0 && models.TrigramAvailable() {
+		results, err := models.FuzzySearchBooks(r.Context(), query, threshold)
+		if err != nil {
+			respondWithSearchError(w, r, &search.BackendError{Backend: "pg_trgm", Err: err})
+			return
+		}
+		response.Mode = "fuzzy"
+		response.Backend = "pg_trgm"
+		response.Threshold = threshold
+		response.Results = append(response.Results, results...)
+	}
+
+	if len(response.Results) == 0 && models.TrigramAvailable() {
+		suggestion, err := models.SuggestSearchTerm(r.Context(), query)
+		if err != nil {
+			respondWithError(w, r, err)
+			return
+		}
+		response.DidYouMean = suggestion
+	}
+
+	metrics.ObserveSearch(response.Backend, response.Mode, len(response.Results))
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// respondWithSearchError melaporkan kegagalan backend pencarian beserta nama backend-nya.
+// Penyebabnya hanya dicatat di log.
+func respondWithSearchError(w http.ResponseWriter, r *http.Request, err error) {
+	var backendErr *search.BackendError
+	if !errors.As(err, &backendErr) {
+		respondWithError(w, r, err)
+		return
+	}
+	slog.ErrorContext(r.Context(), "Search backend failed", "backend", backendErr.Backend, "error", err)
+	status := http.StatusInternalServerError
+	if errors.Is(models.Classify(err), models.ErrUnavailable) {
+		status = http.StatusServiceUnavailable
+	}
+	utils.RespondWithProblem(w, r, utils.Problem{
+		Type:   utils.ProblemTypeSearchBackend,
+		Title:  "Search backend failed",
+		Status: status,
+		Detail: "Search backend " + backendErr.Backend + " failed to answer the query",
+	}.With("backend", backendErr.Backend))
+}
+
+// respondWithSyntaxError melaporkan query berfield yang tidak bisa diurai beserta posisinya
+func respondWithSyntaxError(w http.ResponseWriter, r *http.Request, syntaxErr *searchql.SyntaxError) {
+	utils.RespondWithProblem(w, r, utils.Problem{
+		Type:   utils.ProblemTypeQuerySyntax,
+		Title:  "Invalid search query",
+		Status: http.StatusBadRequest,
+		Detail: syntaxErr.Error(),
+	}.With("position", syntaxErr.Pos))
+}
+
+// Batas waktu dan jumlah hasil untuk endpoint autocomplete
+const (
+	suggestTimeout      = 200 * time.Millisecond
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// SuggestBooksHandler handles type-ahead completion requests
+// @Summary Suggest titles and authors
+// @Description Returns ranked title and author completions starting with the given prefix.
+// @Description Results are cached in-process per prefix and refreshed whenever books change.
+// @Tags books
+// @Produce json
+// @Param prefix query string true "Prefix typed by the user"
+// @Param limit query int false "Maximum number of suggestions (default 10, max 50)"
+// @Success 200 {object} models.SuggestResponse "Ranked completions"
+// @Failure 400 {object} utils.Problem "Prefix is missing or blank, or limit is invalid"
+// @Failure 429 {object} utils.Problem{retry_after=int} "Search rate limit exceeded; see Retry-After"
+// @Failure 503 {object} utils.Problem "Suggestion lookup exceeded its latency budget"
+// @Failure 500 {object} utils.Problem "Internal server error"
+// @Router /books/suggest [get]
+func SuggestBooksHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	prefix := params.Get("prefix")
+	if strings.TrimSpace(prefix) == "" {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameter 'prefix' must not be blank")
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if raw := params.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSuggestLimit {
+			utils.RespondWithError(w, r, http.StatusBadRequest, "Query parameter 'limit' must be between 1 and 50")
+			return
+		}
+		limit = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), suggestTimeout)
+	defer cancel()
+
+	suggestions, err := models.SuggestBooks(ctx, prefix, limit)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			utils.RespondWithError(w, r, http.StatusServiceUnavailable, "Suggestion lookup timed out")
+			return
+		}
+		respondWithError(w, r, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, models.SuggestResponse{Prefix: prefix, Suggestions: suggestions})
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/search"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// setupBooks memasang database SQLite sementara sebagai penyimpanan buku
+func setupBooks(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", config.SQLiteConnString(filepath.Join(t.TempDir(), "books.db")))
+	if err != nil {
+		t.Fatalf("membuka SQLite: %v", err)
+	}
+	if err := config.MigrateSQLite(context.Background(), db); err != nil {
+		t.Fatalf("migrasi SQLite: %v", err)
+	}
+	previousDB, previousStore := config.DB, models.Store
+	config.DB, models.Store = db, models.SQLiteStore{}
+	t.Cleanup(func() {
+		config.DB, models.Store = previousDB, previousStore
+		db.Close()
+	})
+}
+
+func TestPatchBookNotFound(t *testing.T) {
+	setupBooks(t)
+	req := httptest.NewRequest(http.MethodPatch, "/api/books/42", strings.NewReader(`{"title":"Baru"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "42"})
+	rec := httptest.NewRecorder()
+	PatchBookHandler(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, seharusnya 404: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Header().Get("Content-Type"), "problem+json") {
+		t.Errorf("Content-Type = %q, seharusnya problem+json", rec.Header().Get("Content-Type"))
+	}
+	var problem struct {
+		Status int    `json:"status"`
+		Detail string `json:"detail"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("body bukan JSON: %v", err)
+	}
+	if problem.Status != http.StatusNotFound || problem.Detail == "" {
+		t.Errorf("problem = %+v, seharusnya status 404 dengan detail", problem)
+	}
+}
+
+func TestSuggestBooksRejectsBlankPrefix(t *testing.T) {
+	setupBooks(t)
+	if err := models.CreateBook(context.Background(), &models.Book{Title: "Dune", Author: "Frank Herbert", Year: 1965}); err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+	for _, prefix := range []string{"", "%20%20", "%09"} {
+		rec := httptest.NewRecorder()
+		SuggestBooksHandler(rec, httptest.NewRequest(http.MethodGet, "/api/books/suggest?prefix="+prefix, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("prefix %q: status = %d, seharusnya 400: %s", prefix, rec.Code, rec.Body)
+		}
+	}
+
+	suggestions, err := models.SuggestBooks(context.Background(), "   ", 10)
+	if err != nil {
+		t.Fatalf("SuggestBooks: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("prefix kosong menghasilkan %d kandidat, seharusnya tidak ada", len(suggestions))
+	}
+}
+
+func TestSearchFieldedQueryUsesConfiguredBackend(t *testing.T) {
+	setupBooks(t)
+	if err := models.CreateBook(context.Background(), &models.Book{Title: "Filosofi Kopi", Author: "Dee Lestari", Year: 2006}); err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+	previous := search.Active
+	search.Active = search.NewMemorySearcher()
+	t.Cleanup(func() { search.Active = previous })
+
+	rec := httptest.NewRecorder()
+	SearchBooksHandler(rec, httptest.NewRequest(http.MethodGet, "/api/books/search?q=author:lestari+year:2000..", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, seharusnya 200: %s", rec.Code, rec.Body)
+	}
+	var response models.SearchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("body bukan JSON: %v", err)
+	}
+	if response.Backend != search.BackendMemory || response.Mode != "query" || len(response.Results) != 1 {
+		t.Errorf("response = backend %q, mode %q, %d hasil; seharusnya memory, query, 1 hasil",
+			response.Backend, response.Mode, len(response.Results))
+	}
+
+	for _, param := range []string{"explain=true", "fuzzy=true"} {
+		rec := httptest.NewRecorder()
+		SearchBooksHandler(rec, httptest.NewRequest(http.MethodGet, "/api/books/search?q=author:lestari&"+param, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, seharusnya 400", param, rec.Code)
+		}
+	}
+}
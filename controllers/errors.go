@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"crud-buku-go/models"
+	"crud-buku-go/utils"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// respondWithError memetakan err ke response problem+json. Kesalahan payload dan
+// kesalahan domain dari models dipetakan ke status HTTP sesuai jenisnya dan pesannya
+// boleh dibaca klien. Kesalahan lain hanya dicatat di log; klien menerima pesan umum
+// dan request ID untuk menelusurinya.
+func respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	err = models.Classify(err)
+
+	var validation *models.ValidationError
+	var domain *models.Error
+	switch {
+	case errors.Is(err, errBodyTooLarge):
+		utils.RespondWithError(w, r, http.StatusRequestEntityTooLarge, "Body request maksimal "+strconv.Itoa(maxJSONBodySize>>10)+" KiB")
+	case errors.Is(err, errMalformedBody):
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Payload request bukan JSON yang valid")
+	case errors.As(err, &validation):
+		utils.RespondWithProblem(w, r, utils.Problem{
+			Type:   utils.ProblemTypeValidation,
+			Title:  "Data tidak valid",
+			Status: http.StatusUnprocessableEntity,
+			Detail: "Periksa errors untuk kesalahan pada setiap field",
+		}.With("errors", validation.Errors))
+	case errors.Is(err, models.ErrUnavailable):
+		slog.ErrorContext(r.Context(), "Penyimpanan data tidak tersedia", "error", err)
+		utils.RespondWithError(w, r, http.StatusServiceUnavailable, "Layanan sedang tidak tersedia, coba lagi nanti")
+	case errors.As(err, &domain):
+		if domain.Err != nil {
+			slog.WarnContext(r.Context(), "Request ditolak karena kesalahan data", "error", err)
+		}
+		utils.RespondWithError(w, r, domainStatus(domain.Kind), domain.Message)
+	default:
+		slog.ErrorContext(r.Context(), "Kesalahan internal", "error", err)
+		utils.RespondWithError(w, r, http.StatusInternalServerError, "Terjadi kesalahan pada server")
+	}
+}
+
+// domainStatus mengembalikan status HTTP untuk jenis kesalahan domain
+func domainStatus(kind error) int {
+	switch kind {
+	case models.ErrNotFound:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	case models.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case models.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"crud-buku-go/config"
+	"crud-buku-go/health"
+	"crud-buku-go/utils"
+	"net/http"
+)
+
+// Endpoint health berada di luar /api dan tidak didokumentasikan di Swagger,
+// karena ditujukan untuk orchestrator (probe liveness/readiness), bukan klien API.
+
+// LivenessHandler menjawab /healthz: proses hidup dan bisa melayani HTTP.
+// Tidak memeriksa dependensi agar gangguan database tidak membuat proses di-restart.
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": health.StatusOK})
+}
+
+// ReadinessHandler menjawab /readyz dengan rincian setiap pemeriksaan dependensi.
+// Status 503 dikembalikan jika salah satu pemeriksaan gagal, termasuk selama shutdown.
+func ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := health.Ready(r.Context(), config.App.App.HealthTimeout)
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	utils.RespondWithJSON(w, status, report)
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"crud-buku-go/auth"
+	"crud-buku-go/models"
+	"crud-buku-go/oidc"
+	"crud-buku-go/utils"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookie sementara yang menyimpan state, nonce, dan code verifier selama pengguna berada
+// di halaman login identity provider
+const (
+	oidcStateCookie = "crud_buku_oidc"
+	oidcStatePath   = "/api/auth/oidc"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+// oidcLoginState adalah isi cookie oidcStateCookie
+type oidcLoginState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	ReturnTo     string `json:"return_to"`
+}
+
+// @Description Pengguna yang sedang login
+// CurrentUser adalah response endpoint /auth/me
+type CurrentUser struct {
+	ID          int               `json:"id,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Role        string            `json:"role,omitempty"`
+	APIKeyID    int               `json:"api_key_id,omitempty"`
+	APIKeyName  string            `json:"api_key_name,omitempty"`
+	Permissions []auth.Permission `json:"permissions"`
+}
+
+// OIDCLoginHandler menghandle awal login OIDC
+// @Summary Login lewat OpenID Connect
+// @Description Mengarahkan browser ke halaman login identity provider (authorization code flow dengan PKCE).
+// @Description Setelah login berhasil, callback menyimpan sesi di cookie HttpOnly lalu mengarahkan ke return_to.
+// @Tags auth
+// @Param return_to query string false "Path relatif tujuan setelah login" default(/)
+// @Success 302 "Redirect ke authorization endpoint provider"
+// @Failure 404 {object} utils.Problem "Login OIDC tidak aktif"
+// @Failure 502 {object} utils.Problem "Identity provider tidak bisa dihubungi"
+// @Router /auth/oidc/login [get]
+func OIDCLoginHandler(w http.ResponseWriter, r *http.Request) {
+	client := oidc.Active()
+	if client == nil {
+		utils.RespondWithError(w, r, http.StatusNotFound, "Login OIDC tidak aktif")
+		return
+	}
+
+	state := oidcLoginState{
+		State:        oidc.RandomString(),
+		Nonce:        oidc.RandomString(),
+		CodeVerifier: oidc.RandomString(),
+		ReturnTo:     safeReturnTo(r.URL.Query().Get("return_to")),
+	}
+	authURL, err := client.AuthCodeURL(r.Context(), state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Gagal memulai login OIDC", "error", err)
+		utils.RespondWithError(w, r, http.StatusBadGateway, "Identity provider tidak bisa dihubungi")
+		return
+	}
+	if err := auth.SetSignedCookie(w, oidcStateCookie, oidcStatePath, state, oidcStateTTL); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallbackHandler menghandle redirect kembali dari identity provider
+// @Summary Callback login OpenID Connect
+// @Description Memeriksa state, menukar authorization code, memverifikasi ID token, lalu membuat atau
+// @Description memperbarui akun pengguna (peran dipetakan dari klaim provider) dan menyimpan sesi di cookie.
+// @Tags auth
+// @Param code query string false "Authorization code dari provider"
+// @Param state query string true "State yang dikirim saat login dimulai"
+// @Success 302 "Login berhasil, redirect ke return_to"
+// @Failure 400 {object} utils.Problem "State tidak cocok atau sesi login kedaluwarsa"
+// @Failure 401 {object} utils.Problem "Login ditolak provider atau ID token tidak valid"
+// @Failure 403 {object} utils.Problem "Akun tidak memiliki peran di aplikasi"
+// @Failure 404 {object} utils.Problem "Login OIDC tidak aktif"
+// @Failure 409 {object} utils.Problem "Username sudah dipakai akun lokal"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Router /auth/oidc/callback [get]
+func OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	client := oidc.Active()
+	if client == nil {
+		utils.RespondWithError(w, r, http.StatusNotFound, "Login OIDC tidak aktif")
+		return
+	}
+
+	var state oidcLoginState
+	if err := auth.ReadSignedCookie(r, oidcStateCookie, &state); err != nil {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Sesi login OIDC tidak ditemukan atau kedaluwarsa, ulangi login")
+		return
+	}
+	// State hanya boleh dipakai sekali
+	auth.ClearCookie(w, oidcStateCookie, oidcStatePath)
+	w.Header().Set("Cache-Control", "no-store")
+
+	q := r.URL.Query()
+	if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state.State)) != 1 {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "State login OIDC tidak cocok")
+		return
+	}
+	if providerErr := q.Get("error"); providerErr != "" {
+		slog.WarnContext(r.Context(), "Login OIDC ditolak provider", "error", providerErr,
+			"error_description", q.Get("error_description"))
+		utils.RespondWithError(w, r, http.StatusUnauthorized, "Login ditolak identity provider: "+providerErr)
+		return
+	}
+	if q.Get("code") == "" {
+		utils.RespondWithError(w, r, http.StatusBadRequest, "Authorization code tidak ada")
+		return
+	}
+
+	identity, err := client.Exchange(r.Context(), q.Get("code"), state.CodeVerifier, state.Nonce)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Login OIDC gagal", "error", err)
+		if errors.Is(err, oidc.ErrRoleDenied) {
+			utils.RespondWithError(w, r, http.StatusForbidden, err.Error())
+		} else {
+			utils.RespondWithError(w, r, http.StatusUnauthorized, "Login OIDC gagal")
+		}
+		return
+	}
+
+	user := models.User{
+		Username:     identity.Username,
+		Role:         string(identity.Role),
+		ExternalID:   identity.ExternalID,
+		PasswordHash: auth.NoPassword,
+	}
+	if err := models.SyncExternalUser(r.Context(), &user); err != nil {
+		if errors.Is(err, models.ErrUsernameTaken) {
+			slog.WarnContext(r.Context(), "Login OIDC ditolak, username sudah dipakai akun lain",
+				"username", identity.Username, "external_id", identity.ExternalID)
+			utils.RespondWithError(w, r, http.StatusConflict, "Username "+identity.Username+" sudah dipakai akun lain")
+		} else {
+			respondWithError(w, r, err)
+		}
+		return
+	}
+
+	if err := auth.IssueSession(w, user.ID, user.Username, identity.Role); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	slog.InfoContext(r.Context(), "Login OIDC berhasil", "user_id", user.ID, "username", user.Username,
+		"role", user.Role, "external_id", user.ExternalID)
+	http.Redirect(w, r, state.ReturnTo, http.StatusFound)
+}
+
+// LogoutHandler menghandle request logout sesi cookie
+// @Summary Logout
+// @Description Menghapus cookie sesi hasil login OIDC. Token Bearer tidak terpengaruh dan tetap berlaku sampai kedaluwarsa.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string "Sesi dihapus"
+// @Router /auth/logout [post]
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	auth.ClearSession(w)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Logout berhasil"})
+}
+
+// MeHandler menghandle request data pengguna yang sedang login
+// @Summary Pengguna saat ini
+// @Description Mengembalikan identitas dan izin pemanggil, dari token Bearer, API key, atau cookie sesi.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} CurrentUser "Pemanggil yang terautentikasi"
+// @Failure 401 {object} utils.Problem "Belum login"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/me [get]
+func MeHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, r, http.StatusUnauthorized, "Autentikasi dibutuhkan")
+		return
+	}
+	current := CurrentUser{
+		ID:          principal.UserID,
+		Username:    principal.Username,
+		Role:        string(principal.Role),
+		APIKeyID:    principal.APIKeyID,
+		APIKeyName:  principal.APIKeyName,
+		Permissions: principal.Permissions(),
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	utils.RespondWithJSON(w, http.StatusOK, current)
+}
+
+// safeReturnTo hanya menerima path relatif di aplikasi ini agar callback tidak bisa
+// dipakai sebagai open redirect
+func safeReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.ContainsAny(returnTo, "\\\r\n") {
+		return "/"
+	}
+	return returnTo
+}
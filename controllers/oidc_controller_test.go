@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/oidc"
+	"crud-buku-go/oidc/oidctest"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testRedirectURL = "http://app.test/api/auth/oidc/callback"
+	testClientID    = "crud-buku"
+	testSecret      = "rahasia"
+)
+
+// setupOIDC menyiapkan database SQLite sementara, kunci JWT, dan provider tiruan yang
+// menjadi issuer client OIDC aktif
+func setupOIDC(t *testing.T) *oidctest.Server {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", config.SQLiteConnString(filepath.Join(t.TempDir(), "oidc.db")))
+	if err != nil {
+		t.Fatalf("membuka SQLite: %v", err)
+	}
+	if err := config.MigrateSQLite(context.Background(), db); err != nil {
+		t.Fatalf("migrasi SQLite: %v", err)
+	}
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		db.Close()
+	})
+
+	if err := auth.Configure(config.AuthConfig{
+		SigningKeys:         "test:0123456789abcdef0123456789abcdef",
+		Issuer:              "crud-buku-go",
+		AccessTTL:           time.Minute,
+		RefreshTTL:          time.Hour,
+		SessionTTL:          time.Hour,
+		SessionCookieSecure: true,
+	}); err != nil {
+		t.Fatalf("auth.Configure: %v", err)
+	}
+
+	provider := oidctest.NewServer(testClientID, testSecret, testRedirectURL)
+	t.Cleanup(provider.Close)
+	if err := oidc.Configure(config.OIDCConfig{
+		Issuer:        provider.URL,
+		ClientID:      testClientID,
+		ClientSecret:  testSecret,
+		RedirectURL:   testRedirectURL,
+		Scopes:        "openid profile",
+		UsernameClaim: "preferred_username",
+		RoleClaim:     "groups",
+		RoleMapping:   "perpus-staf=librarian,perpus-admin=admin",
+	}); err != nil {
+		t.Fatalf("oidc.Configure: %v", err)
+	}
+	return provider
+}
+
+// startLogin menjalankan OIDCLoginHandler lalu mengikuti redirect ke provider. Hasilnya
+// adalah URL callback dari provider dan cookie state yang dipasang saat login dimulai.
+func startLogin(t *testing.T, returnTo string) (*url.URL, *http.Cookie) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	OIDCLoginHandler(rec, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login?return_to="+url.QueryEscape(returnTo), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login: status = %d, seharusnya 302: %s", rec.Code, rec.Body)
+	}
+	stateCookie := findCookie(rec.Result().Cookies(), oidcStateCookie)
+	if stateCookie == nil {
+		t.Fatal("login: cookie state tidak dipasang")
+	}
+	if stateCookie.Path != oidcStatePath || !stateCookie.HttpOnly || !stateCookie.Secure {
+		t.Errorf("login: cookie state = %+v, seharusnya HttpOnly, Secure, path %s", stateCookie, oidcStatePath)
+	}
+
+	authorizeURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("login: Location tidak valid: %v", err)
+	}
+	q := authorizeURL.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("state") == "" || q.Get("nonce") == "" {
+		t.Fatalf("login: authorization request tanpa PKCE S256, state, atau nonce: %s", authorizeURL)
+	}
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.Get(authorizeURL.String())
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status = %d, seharusnya 302", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: Location tidak valid: %v", err)
+	}
+	if callback.Query().Get("state") != q.Get("state") {
+		t.Fatalf("authorize: state %q tidak dikembalikan apa adanya", q.Get("state"))
+	}
+	return callback, stateCookie
+}
+
+// finishLogin memanggil OIDCCallbackHandler dengan URL callback dan cookie state
+func finishLogin(callback *url.URL, stateCookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	if stateCookie != nil {
+		req.AddCookie(stateCookie)
+	}
+	rec := httptest.NewRecorder()
+	OIDCCallbackHandler(rec, req)
+	return rec
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestOIDCLoginRoundTrip(t *testing.T) {
+	provider := setupOIDC(t)
+	provider.SetClaims(map[string]interface{}{
+		"sub":                "staf-1",
+		"preferred_username": "Budi",
+		"groups":             []string{"lain", "perpus-staf", "perpus-admin"},
+	})
+
+	callback, stateCookie := startLogin(t, "/rak/buku")
+	rec := finishLogin(callback, stateCookie)
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/rak/buku" {
+		t.Fatalf("callback: status %d ke %q, seharusnya 302 ke /rak/buku: %s", rec.Code, rec.Header().Get("Location"), rec.Body)
+	}
+
+	cookies := rec.Result().Cookies()
+	session := findCookie(cookies, auth.SessionCookie)
+	if session == nil || session.Value == "" {
+		t.Fatal("callback: cookie sesi tidak dipasang")
+	}
+	if !session.HttpOnly || !session.Secure || session.SameSite != http.SameSiteLaxMode || session.Path != "/" || session.MaxAge != 3600 {
+		t.Errorf("cookie sesi = %+v, seharusnya HttpOnly, Secure, SameSite=Lax, path /, MaxAge 3600", session)
+	}
+	if cleared := findCookie(cookies, oidcStateCookie); cleared == nil || cleared.MaxAge >= 0 {
+		t.Errorf("callback: cookie state tidak dihapus (%+v)", cleared)
+	}
+
+	principal, err := auth.ParseToken(session.Value, auth.TokenSession)
+	if err != nil {
+		t.Fatalf("token sesi tidak valid: %v", err)
+	}
+	if principal.Username != "budi" || principal.Role != auth.RoleAdmin {
+		t.Errorf("sesi untuk %q dengan peran %q, seharusnya budi dengan peran admin", principal.Username, principal.Role)
+	}
+
+	// State dan code hanya boleh dipakai sekali
+	if replay := finishLogin(callback, nil); replay.Code != http.StatusBadRequest {
+		t.Errorf("callback diulang tanpa cookie state: status = %d, seharusnya 400", replay.Code)
+	}
+}
+
+func TestOIDCCallbackRejectsStateMismatch(t *testing.T) {
+	setupOIDC(t)
+	callback, stateCookie := startLogin(t, "/")
+	q := callback.Query()
+	q.Set("state", "state-lain")
+	callback.RawQuery = q.Encode()
+
+	rec := finishLogin(callback, stateCookie)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, seharusnya 400: %s", rec.Code, rec.Body)
+	}
+	if findCookie(rec.Result().Cookies(), auth.SessionCookie) != nil {
+		t.Error("cookie sesi dipasang walaupun state tidak cocok")
+	}
+}
+
+func TestOIDCCallbackRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim string
+		value interface{}
+	}{
+		{"nonce", "nonce", "nonce-lain"},
+		{"audience", "aud", "client-lain"},
+		{"issuer", "iss", "https://issuer-lain.example"},
+		{"kedaluwarsa", "exp", time.Now().Add(-time.Hour).Unix()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := setupOIDC(t)
+			provider.SetClaims(map[string]interface{}{
+				"sub":                "staf-1",
+				"preferred_username": "budi",
+				"groups":             "perpus-staf",
+				tt.claim:             tt.value,
+			})
+
+			callback, stateCookie := startLogin(t, "/")
+			rec := finishLogin(callback, stateCookie)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, seharusnya 401: %s", rec.Code, rec.Body)
+			}
+			if findCookie(rec.Result().Cookies(), auth.SessionCookie) != nil {
+				t.Error("cookie sesi dipasang untuk ID token yang tidak valid")
+			}
+		})
+	}
+}
+
+func TestOIDCRoleMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups interface{}
+		status int
+		role   auth.Role
+	}{
+		{"grup tunggal", "perpus-staf", http.StatusFound, auth.RoleLibrarian},
+		{"peran tertinggi", []string{"perpus-admin", "perpus-staf"}, http.StatusFound, auth.RoleAdmin},
+		{"tanpa grup yang dipetakan", []string{"lain"}, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := setupOIDC(t)
+			provider.SetClaims(map[string]interface{}{
+				"sub":                "staf-1",
+				"preferred_username": "budi",
+				"groups":             tt.groups,
+			})
+
+			callback, stateCookie := startLogin(t, "/")
+			rec := finishLogin(callback, stateCookie)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, seharusnya %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.role == "" {
+				if !strings.Contains(rec.Header().Get("Content-Type"), "problem+json") {
+					t.Errorf("penolakan tidak berupa problem+json: %q", rec.Header().Get("Content-Type"))
+				}
+				return
+			}
+			session := findCookie(rec.Result().Cookies(), auth.SessionCookie)
+			if session == nil {
+				t.Fatal("cookie sesi tidak dipasang")
+			}
+			principal, err := auth.ParseToken(session.Value, auth.TokenSession)
+			if err != nil {
+				t.Fatalf("token sesi tidak valid: %v", err)
+			}
+			if principal.Role != tt.role {
+				t.Errorf("peran = %q, seharusnya %q", principal.Role, tt.role)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"crud-buku-go/models"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxJSONBodySize membatasi ukuran body JSON; payload buku jauh lebih kecil dari ini
+const maxJSONBodySize = 64 << 10
+
+// Kesalahan decodeJSON yang bukan kesalahan field
+var (
+	errBodyTooLarge  = errors.New("body request terlalu besar")
+	errMalformedBody = errors.New("payload request tidak valid")
+)
+
+// decodeJSON membaca tepat satu objek JSON dari body ke v. Field yang tidak dikenal dan
+// tipe yang salah dikembalikan sebagai *models.ValidationError.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodySize)
+	defer r.Body.Close()
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		// Sisa data setelah objek pertama berarti payload bukan satu objek JSON
+		if _, extra := decoder.Token(); extra != io.EOF {
+			return errMalformedBody
+		}
+		return nil
+	}
+
+	var maxBytes *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &maxBytes):
+		return errBodyTooLarge
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		var v models.ValidationError
+		v.Add(typeErr.Field, models.CodeInvalidType, "Field "+typeErr.Field+" harus bertipe "+typeErr.Type.String())
+		return &v
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		var v models.ValidationError
+		v.Add(field, models.CodeUnknownField, "Field "+field+" tidak dikenal")
+		return &v
+	}
+	return errMalformedBody
+}
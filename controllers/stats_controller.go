@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/searchql"
+	"crud-buku-go/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Nilai bawaan dan batas parameter statistik
+const (
+	defaultTopAuthors = 5
+	maxTopAuthors     = 50
+	defaultYearBucket = 5
+	maxYearBucket     = 100
+)
+
+// GetStatsHandler menghandle request untuk statistik katalog buku
+// @Summary Statistik katalog buku
+// @Description Mengembalikan jumlah buku per penulis, per dekade terbit, buku yang ditambahkan per bulan,
+// @Description penulis teratas, dan histogram tahun terbit. Hasil dihitung dengan agregasi SQL dan
+// @Description disimpan di cache selama STATS_CACHE_TTL (bawaan 30 detik).
+// @Tags stats
+// @Produce json
+// @Param q query string false "Filter dengan sintaks query pencarian, misalnya author:\"Dee Lestari\" year:2000..2010"
+// @Param top query int false "Jumlah penulis teratas (bawaan 5, maksimal 50)"
+// @Param bucket query int false "Lebar batang histogram tahun (bawaan 5, maksimal 100)"
+// @Success 200 {object} models.CatalogStats "Statistik katalog"
+// @Failure 400 {object} utils.Problem "Parameter tidak valid"
+// @Failure 500 {object} utils.Problem "Kesalahan server internal"
+// @Router /stats [get]
+func GetStatsHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	top, ok := intParam(w, r, params.Get("top"), "top", defaultTopAuthors, 1, maxTopAuthors)
+	if !ok {
+		return
+	}
+	bucket, ok := intParam(w, r, params.Get("bucket"), "bucket", defaultYearBucket, 1, maxYearBucket)
+	if !ok {
+		return
+	}
+
+	filter := models.StatsFilter{TopAuthors: top, BucketSize: bucket}
+	query := params.Get("q")
+	if query != "" {
+		parsedQuery, err := searchql.Parse(query)
+		if err != nil {
+			var syntaxErr *searchql.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				respondWithSyntaxError(w, r, syntaxErr)
+				return
+			}
+			utils.RespondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Where, filter.Args = searchql.Compile(parsedQuery, 1)
+	}
+
+	cacheKey := fmt.Sprintf("%s\x00%d\x00%d", query, top, bucket)
+	stats, err := models.GetCatalogStats(r.Context(), filter, cacheKey)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, stats)
+}
+
+// intParam membaca parameter angka dalam rentang [min, max]; jika tidak valid,
+// response 400 langsung dikirim dan ok bernilai false.
+func intParam(w http.ResponseWriter, r *http.Request, raw, name string, fallback, min, max int) (value int, ok bool) {
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		utils.RespondWithError(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Query parameter '%s' must be between %d and %d", name, min, max))
+		return 0, false
+	}
+	return value, true
+}
+
+// GetCacheStatsHandler menghandle request untuk statistik cache buku
+// @Summary Statistik cache buku
+// @Description Mengembalikan jumlah hit, miss, eviction, dan entri kedaluwarsa dari cache baca buku.
+// @Tags stats
+// @Produce json
+// @Success 200 {object} cache.Stats "Statistik cache"
+// @Failure 404 {object} utils.Problem "Cache buku tidak aktif"
+// @Router /stats/cache [get]
+func GetCacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	stats, ok := models.CacheStats()
+	if !ok {
+		utils.RespondWithError(w, r, http.StatusNotFound, "Cache buku tidak aktif")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, stats)
+}
+
+// GetDBStatsHandler menghandle request untuk statistik pool koneksi database
+// @Summary Statistik pool database
+// @Description Mengembalikan jumlah koneksi terbuka, sedang dipakai, idle, serta statistik antrean pool database.
+// @Description Membutuhkan izin config:read (peran admin).
+// @Tags stats
+// @Produce json
+// @Success 200 {object} config.PoolStats "Statistik pool database"
+// @Failure 401 {object} utils.Problem "Autentikasi dibutuhkan atau token tidak valid"
+// @Failure 403 {object} utils.Problem "Izin config:read tidak dimiliki"
+// @Security BearerAuth
+// @Router /stats/db [get]
+func GetDBStatsHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, config.GetPoolStats())
+}
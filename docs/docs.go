@@ -24,33 +24,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/books": {
+        "/admin/api-keys": {
             "get": {
-                "description": "Mengambil daftar semua buku dari database.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Mengembalikan semua API key beserta scope, waktu kedaluwarsa, pemakaian terakhir, dan\nstatus pencabutan, tanpa nilai key maupun hash-nya. Membutuhkan izin apikeys:manage.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "admin"
                 ],
-                "summary": "Mendapatkan semua buku",
+                "summary": "Daftar API key",
                 "responses": {
                     "200": {
-                        "description": "Daftar semua buku",
+                        "description": "Daftar API key",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Book"
+                                "$ref": "#/definitions/models.APIKey"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin apikeys:manage tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Menambahkan buku baru ke database.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Membuat API key untuk klien mesin dengan scope books:read, books:write, dan/atau import.\nKey dikirim di header \"Authorization: ApiKey \u003ckey\u003e\" dan hanya ditampilkan sekali di response ini;\nserver hanya menyimpan hash-nya. Membutuhkan izin apikeys:manage (peran admin).",
                 "consumes": [
                     "application/json"
                 ],
@@ -58,158 +83,250 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "admin"
                 ],
-                "summary": "Membuat buku baru",
+                "summary": "Membuat API key",
                 "parameters": [
                     {
-                        "description": "Data buku baru",
-                        "name": "book",
+                        "description": "Nama, scope, dan waktu kedaluwarsa (opsional)",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/controllers.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Buku berhasil dibuat",
+                        "description": "API key berhasil dibuat",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/controllers.CreatedAPIKey"
                         }
                     },
                     "400": {
-                        "description": "Payload request tidak valid atau data buku tidak lengkap",
+                        "description": "Payload, scope, atau waktu kedaluwarsa tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin apikeys:manage tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Kesalahan server internal",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
         },
-        "/books/search": {
-            "get": {
-                "description": "Search books by title, author, or year",
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mencabut API key sehingga langsung tidak bisa dipakai lagi. Key yang dicabut tetap\ntampil di daftar untuk keperluan audit. Membutuhkan izin apikeys:manage.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "admin"
                 ],
-                "summary": "Search books",
+                "summary": "Mencabut API key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search query (can be title, author, or year)",
-                        "name": "q",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "ID API key",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of matching books",
+                        "description": "API key berhasil dicabut",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Book"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
                     "400": {
-                        "description": "Search query is required",
+                        "description": "ID API key tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin apikeys:manage tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "API key tidak ditemukan atau sudah dicabut",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Kesalahan server internal",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
         },
-        "/books/{id}": {
+        "/admin/config": {
             "get": {
-                "description": "Mengambil detail buku berdasarkan ID.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Mengembalikan konfigurasi aplikasi yang sedang berlaku dengan nilai rahasia disamarkan.\nMembutuhkan izin config:read (peran admin).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "admin"
                 ],
-                "summary": "Mendapatkan buku berdasarkan ID",
-                "parameters": [
+                "summary": "Dump konfigurasi aktif",
+                "responses": {
+                    "200": {
+                        "description": "Konfigurasi aktif (tersamarkan)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin config:read tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/permissions": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "ID Buku",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Mengembalikan izin setiap peran (reader, librarian, admin) dan izin yang dibutuhkan setiap rute.\nMembutuhkan izin users:manage (peran admin).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Matriks izin",
                 "responses": {
                     "200": {
-                        "description": "Detail buku",
+                        "description": "Matriks izin",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/auth.PermissionMatrix"
                         }
                     },
-                    "400": {
-                        "description": "ID buku tidak valid",
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Buku tidak ditemukan",
+                    "403": {
+                        "description": "Izin users:manage tidak dimiliki",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengembalikan semua pengguna beserta perannya. Membutuhkan izin users:manage (peran admin).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Daftar pengguna",
+                "responses": {
+                    "200": {
+                        "description": "Daftar pengguna",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin users:manage tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Kesalahan server internal",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/role": {
             "put": {
-                "description": "Memperbarui data buku berdasarkan ID.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengganti peran pengguna menjadi reader, librarian, atau admin. Peran baru berlaku\nsetelah pengguna login ulang atau menukar token refresh. Membutuhkan izin users:manage.",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,65 +334,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "admin"
                 ],
-                "summary": "Memperbarui buku",
+                "summary": "Mengganti peran pengguna",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Buku",
+                        "description": "ID Pengguna",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data buku yang diperbarui",
-                        "name": "book",
+                        "description": "Peran baru",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/controllers.UpdateRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Buku berhasil diperbarui",
+                        "description": "Pengguna dengan peran baru",
                         "schema": {
-                            "$ref": "#/definitions/models.Book"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
                     "400": {
-                        "description": "ID buku tidak valid atau payload request tidak valid",
+                        "description": "ID pengguna, payload, atau peran tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin users:manage tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "404": {
-                        "description": "Buku tidak ditemukan untuk diperbarui",
+                        "description": "Pengguna tidak ditemukan",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Kesalahan server internal",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Menghapus buku berdasarkan ID.",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Memeriksa username dan password, lalu menerbitkan token akses (Bearer) dan token refresh.\nToken akses dipakai di header Authorization; izinnya mengikuti peran pengguna (reader, librarian, admin).",
                 "consumes": [
                     "application/json"
                 ],
@@ -283,48 +405,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "auth"
                 ],
-                "summary": "Menghapus buku",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Buku",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Username dan password",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pesan sukses penghapusan",
+                        "description": "Token berhasil diterbitkan",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/auth.TokenPair"
                         }
                     },
                     "400": {
-                        "description": "ID buku tidak valid",
+                        "description": "Payload request tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Buku tidak ditemukan untuk dihapus",
+                    "401": {
+                        "description": "Username atau password salah",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Menghapus cookie sesi hasil login OIDC. Token Bearer tidak terpengaruh dan tetap berlaku sampai kedaluwarsa.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "Sesi dihapus",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -333,29 +468,242 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Memperbarui sebagian data buku (judul, penulis, atau tahun) berdasarkan ID.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Mengembalikan identitas dan izin pemanggil, dari token Bearer, API key, atau cookie sesi.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "auth"
                 ],
-                "summary": "Memperbarui sebagian data buku",
+                "summary": "Pengguna saat ini",
+                "responses": {
+                    "200": {
+                        "description": "Pemanggil yang terautentikasi",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CurrentUser"
+                        }
+                    },
+                    "401": {
+                        "description": "Belum login",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Memeriksa state, menukar authorization code, memverifikasi ID token, lalu membuat atau\nmemperbarui akun pengguna (peran dipetakan dari klaim provider) dan menyimpan sesi di cookie.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Callback login OpenID Connect",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Buku",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Authorization code dari provider",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State yang dikirim saat login dimulai",
+                        "name": "state",
+                        "in": "query",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Login berhasil, redirect ke return_to"
+                    },
+                    "400": {
+                        "description": "State tidak cocok atau sesi login kedaluwarsa",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
                     },
+                    "401": {
+                        "description": "Login ditolak provider atau ID token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Akun tidak memiliki peran di aplikasi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Login OIDC tidak aktif",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Username sudah dipakai akun lokal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Mengarahkan browser ke halaman login identity provider (authorization code flow dengan PKCE).\nSetelah login berhasil, callback menyimpan sesi di cookie HttpOnly lalu mengarahkan ke return_to.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login lewat OpenID Connect",
+                "parameters": [
                     {
-                        "description": "Data buku yang akan diperbarui (hanya field yang ingin diubah)",
+                        "type": "string",
+                        "default": "/",
+                        "description": "Path relatif tujuan setelah login",
+                        "name": "return_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect ke authorization endpoint provider"
+                    },
+                    "404": {
+                        "description": "Login OIDC tidak aktif",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider tidak bisa dihubungi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Menukar token refresh yang masih berlaku dengan pasangan token akses dan refresh baru.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh token",
+                "parameters": [
+                    {
+                        "description": "Token refresh",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token berhasil diterbitkan",
+                        "schema": {
+                            "$ref": "#/definitions/auth.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Payload request tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Token refresh tidak valid atau kedaluwarsa",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/books": {
+            "get": {
+                "description": "Mengambil daftar semua buku dari database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Mendapatkan semua buku",
+                "responses": {
+                    "200": {
+                        "description": "Daftar semua buku",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Book"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menambahkan buku baru ke database. Spasi di awal dan akhir judul dan penulis dibuang;\njudul dan penulis maksimal 255 karakter, tahun antara 1000 dan tahun berjalan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Membuat buku baru",
+                "parameters": [
+                    {
+                        "description": "Data buku baru",
                         "name": "book",
                         "in": "body",
                         "required": true,
@@ -365,67 +713,1236 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Buku berhasil diperbarui (sebagian)",
+                    "201": {
+                        "description": "Buku berhasil dibuat",
                         "schema": {
                             "$ref": "#/definitions/models.Book"
                         }
                     },
                     "400": {
-                        "description": "ID buku tidak valid atau payload request tidak valid",
+                        "description": "Payload request bukan JSON yang valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Buku tidak ditemukan untuk diperbarui",
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Body request terlalu besar",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Field tidak dikenal, tipe salah, atau data buku tidak valid",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Problem"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
                         "description": "Kesalahan server internal",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Book": {
-            "description": "Struktur data untuk buku",
-            "type": "object",
-            "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "year": {
-                    "type": "integer"
+        },
+        "/books/search": {
+            "get": {
+                "description": "Search books by title, author, or year using the configured search backend (SEARCH_BACKEND:\npostgres, like, memory or sqlite); the backend that served the results is reported in the response.\nWhen the exact search finds nothing (or fuzzy=true),\na typo-tolerant trigram search is used and a \"did you mean\" suggestion is returned on zero hits.\nFuzzy search and suggestions need PostgreSQL and are unavailable with DB_DRIVER=sqlite.\nThe query also accepts a fielded syntax, e.g. ` + "`" + `author:\"Dee Lestari\" year:2005..2012 -title:filosofi` + "`" + `,\nwith field prefixes (title, author, year), year ranges, quoted phrases, negation (-), OR and parentheses.\nFielded queries run on the configured backend, are ordered by title and do not accept fuzzy or explain.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Search books",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (plain text or fielded query syntax)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Force trigram similarity search",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Trigram similarity threshold between 0 and 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include each hit's score breakdown",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching books",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Search query is required, malformed, threshold is invalid, fuzzy is unavailable, or fuzzy/explain was combined with a fielded query",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Search rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Problem"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "retry_after": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Search backend failed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
                 }
             }
+        },
+        "/books/suggest": {
+            "get": {
+                "description": "Returns ranked title and author completions starting with the given prefix.\nResults are cached in-process per prefix and refreshed whenever books change.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Suggest titles and authors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix typed by the user",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of suggestions (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked completions",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuggestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Prefix is required or limit is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Search rate limit exceeded; see Retry-After",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Problem"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "retry_after": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Suggestion lookup exceeded its latency budget",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}": {
+            "get": {
+                "description": "Mengambil detail buku berdasarkan ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Mendapatkan buku berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Buku",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Detail buku",
+                        "schema": {
+                            "$ref": "#/definitions/models.Book"
+                        }
+                    },
+                    "400": {
+                        "description": "ID buku tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Buku tidak ditemukan",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Memperbarui data buku berdasarkan ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Memperbarui buku",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Buku",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data buku yang diperbarui",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Book"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Buku berhasil diperbarui",
+                        "schema": {
+                            "$ref": "#/definitions/models.Book"
+                        }
+                    },
+                    "400": {
+                        "description": "ID buku tidak valid atau payload request tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Buku tidak ditemukan untuk diperbarui",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Body request terlalu besar",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Field tidak dikenal, tipe salah, atau data buku tidak valid",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Problem"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus buku berdasarkan ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Menghapus buku",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Buku",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pesan sukses penghapusan",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID buku tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Buku tidak ditemukan untuk dihapus",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Memperbarui sebagian data buku (judul, penulis, atau tahun) berdasarkan ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Memperbarui sebagian data buku",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Buku",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data buku yang akan diperbarui (hanya field yang ingin diubah)",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Book"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Buku berhasil diperbarui (sebagian)",
+                        "schema": {
+                            "$ref": "#/definitions/models.Book"
+                        }
+                    },
+                    "400": {
+                        "description": "ID buku tidak valid atau payload request tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Buku tidak ditemukan untuk diperbarui",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Body request terlalu besar",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Field tidak dikenal, tipe salah, atau data buku hasil gabungan tidak valid",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.Problem"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "errors": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Mengembalikan jumlah buku per penulis, per dekade terbit, buku yang ditambahkan per bulan,\npenulis teratas, dan histogram tahun terbit. Hasil dihitung dengan agregasi SQL dan\ndisimpan di cache selama STATS_CACHE_TTL (bawaan 30 detik).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Statistik katalog buku",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter dengan sintaks query pencarian, misalnya author:\\",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah penulis teratas (bawaan 5, maksimal 50)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lebar batang histogram tahun (bawaan 5, maksimal 100)",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statistik katalog",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Parameter tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/cache": {
+            "get": {
+                "description": "Mengembalikan jumlah hit, miss, eviction, dan entri kedaluwarsa dari cache baca buku.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Statistik cache buku",
+                "responses": {
+                    "200": {
+                        "description": "Statistik cache",
+                        "schema": {
+                            "$ref": "#/definitions/cache.Stats"
+                        }
+                    },
+                    "404": {
+                        "description": "Cache buku tidak aktif",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/db": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengembalikan jumlah koneksi terbuka, sedang dipakai, idle, serta statistik antrean pool database.\nMembutuhkan izin config:read (peran admin).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Statistik pool database",
+                "responses": {
+                    "200": {
+                        "description": "Statistik pool database",
+                        "schema": {
+                            "$ref": "#/definitions/config.PoolStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Autentikasi dibutuhkan atau token tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Izin config:read tidak dimiliki",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.Permission": {
+            "type": "string",
+            "enum": [
+                "books:read",
+                "reviews:write",
+                "books:write",
+                "import",
+                "loans:manage",
+                "users:manage",
+                "apikeys:manage",
+                "config:read"
+            ],
+            "x-enum-varnames": [
+                "PermBooksRead",
+                "PermReviewsWrite",
+                "PermBooksWrite",
+                "PermImport",
+                "PermLoansManage",
+                "PermUsersManage",
+                "PermAPIKeysManage",
+                "PermConfigRead"
+            ]
+        },
+        "auth.PermissionMatrix": {
+            "description": "Matriks izin per peran dan izin yang dibutuhkan setiap rute",
+            "type": "object",
+            "properties": {
+                "api_key_scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Permission"
+                    }
+                },
+                "roles": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/auth.Permission"
+                        }
+                    }
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.RouteRule"
+                    }
+                }
+            }
+        },
+        "auth.RouteRule": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "permission": {
+                    "$ref": "#/definitions/auth.Permission"
+                },
+                "public": {
+                    "description": "Public berarti rute bisa diakses tanpa token (lihat AUTH_PUBLIC_READS)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "auth.TokenPair": {
+            "description": "Pasangan token hasil login atau refresh",
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "cache.Stats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "hit_ratio": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.PoolStats": {
+            "type": "object",
+            "properties": {
+                "idle": {
+                    "type": "integer"
+                },
+                "in_use": {
+                    "type": "integer"
+                },
+                "max_idle_closed": {
+                    "type": "integer"
+                },
+                "max_idle_time_closed": {
+                    "type": "integer"
+                },
+                "max_lifetime_closed": {
+                    "type": "integer"
+                },
+                "max_open_connections": {
+                    "type": "integer"
+                },
+                "open_connections": {
+                    "type": "integer"
+                },
+                "wait_count": {
+                    "type": "integer"
+                },
+                "wait_duration_ms": {
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.CreateAPIKeyRequest": {
+            "description": "Data API key baru",
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "import-katalog-harian"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books:read",
+                        "books:write"
+                    ]
+                }
+            }
+        },
+        "controllers.CreatedAPIKey": {
+            "description": "API key yang baru dibuat. Nilai key hanya ditampilkan sekali.",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controllers.CurrentUser": {
+            "description": "Pengguna yang sedang login",
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "api_key_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Permission"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginRequest": {
+            "description": "Kredensial login",
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "rahasia123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "controllers.RefreshRequest": {
+            "description": "Token refresh yang ditukar dengan pasangan token baru",
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateRoleRequest": {
+            "description": "Peran baru pengguna",
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "reader",
+                        "librarian",
+                        "admin"
+                    ],
+                    "example": "librarian"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Book": {
+            "description": "Struktur data untuk buku",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CatalogStats": {
+            "description": "Statistik katalog buku",
+            "type": "object",
+            "properties": {
+                "added_per_month": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CountByLabel"
+                    }
+                },
+                "books_per_author": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CountByLabel"
+                    }
+                },
+                "books_per_decade": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CountByLabel"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "top_authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CountByLabel"
+                    }
+                },
+                "total_authors": {
+                    "type": "integer"
+                },
+                "total_books": {
+                    "type": "integer"
+                },
+                "year_histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.YearBucket"
+                    }
+                }
+            }
+        },
+        "models.CountByLabel": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldError": {
+            "description": "Kesalahan validasi pada satu field",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "out_of_range"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "year"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Tahun harus antara 1000 dan 2026"
+                }
+            }
+        },
+        "models.ScoreExplanation": {
+            "type": "object",
+            "properties": {
+                "author_rank": {
+                    "type": "number"
+                },
+                "boosts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "recency": {
+                    "type": "number"
+                },
+                "recency_weight": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "text_rank": {
+                    "type": "number"
+                },
+                "title_rank": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SearchResponse": {
+            "description": "Hasil pencarian buku",
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "type": "string"
+                },
+                "did_you_mean": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchResult"
+                    }
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "explain": {
+                    "$ref": "#/definitions/models.ScoreExplanation"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SuggestResponse": {
+            "description": "Hasil autocomplete judul dan penulis",
+            "type": "object",
+            "properties": {
+                "prefix": {
+                    "type": "string"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Suggestion"
+                    }
+                }
+            }
+        },
+        "models.Suggestion": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.YearBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "utils.Problem": {
+            "description": "Detail kesalahan dalam format RFC 7807 (application/problem+json)",
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "buku tidak ditemukan"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/books/42"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key dari /admin/api-keys dengan format \"ApiKey \u003ckey\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Token akses dari /auth/login dengan format \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
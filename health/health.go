@@ -0,0 +1,137 @@
+// Package health menjalankan pemeriksaan kesiapan (readiness) aplikasi dan dependensinya.
+package health
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crud-buku-go/lifecycle"
+	"crud-buku-go/models"
+	"crud-buku-go/search"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status pemeriksaan
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckResult adalah hasil satu pemeriksaan beserta lamanya
+type CheckResult struct {
+	Name      string      `json:"name"`
+	Status    string      `json:"status"`
+	LatencyMs float64     `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// Report adalah gabungan semua pemeriksaan; Status bernilai ok hanya jika semuanya ok
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Healthy melaporkan apakah semua pemeriksaan berhasil
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+// check adalah satu pemeriksaan; details opsional ditampilkan di hasil
+type check struct {
+	name string
+	run  func(ctx context.Context) (details interface{}, err error)
+}
+
+var checks = []check{
+	{"shutdown", checkShutdown},
+	{"database", checkDatabase},
+	{"migrations", checkMigrations},
+	{"search", checkSearch},
+}
+
+// Ready menjalankan semua pemeriksaan secara paralel, masing-masing dibatasi timeout
+func Ready(ctx context.Context, timeout time.Duration) Report {
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = run(ctx, c, timeout)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, c check, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := c.run(ctx)
+	result := CheckResult{
+		Name:      c.name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("melebihi batas waktu %v", timeout)
+		}
+	}
+	return result
+}
+
+func checkShutdown(context.Context) (interface{}, error) {
+	if !lifecycle.Ready() {
+		return nil, errors.New("server sedang shutdown atau belum selesai startup")
+	}
+	return nil, nil
+}
+
+func checkDatabase(ctx context.Context) (interface{}, error) {
+	if config.DB == nil {
+		return nil, errors.New("database belum terhubung")
+	}
+	return map[string]string{"driver": config.App.Database.Driver}, config.DB.PingContext(ctx)
+}
+
+func checkMigrations(ctx context.Context) (interface{}, error) {
+	if config.DB == nil {
+		return nil, errors.New("database belum terhubung")
+	}
+	pending, err := config.PendingMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string][]string{"pending": pending}
+	if len(pending) > 0 {
+		return details, fmt.Errorf("migrasi belum diterapkan: %s", strings.Join(pending, ", "))
+	}
+	return details, nil
+}
+
+// checkSearch menjalankan pencarian kecil pada backend aktif; hasilnya tidak penting,
+// yang diperiksa hanya apakah backend bisa menjawab
+func checkSearch(ctx context.Context) (interface{}, error) {
+	searcher := search.Active
+	details := map[string]string{"backend": searcher.Name()}
+	opts := search.Options{Relevance: models.LoadRelevanceConfig()}
+	_, err := search.Run(ctx, searcher, "readiness", opts)
+	return details, err
+}
@@ -0,0 +1,50 @@
+// Package lifecycle melacak status siap (readiness) proses dan goroutine latar belakang
+// yang harus selesai sebelum aplikasi berhenti, misalnya sebelum pool database ditutup.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ready      atomic.Bool
+	background sync.WaitGroup
+)
+
+// SetReady menandai apakah proses siap menerima trafik baru
+func SetReady(v bool) {
+	ready.Store(v)
+}
+
+// Ready melaporkan apakah proses siap menerima trafik baru. Bernilai false sebelum
+// startup selesai dan sejak shutdown dimulai.
+func Ready() bool {
+	return ready.Load()
+}
+
+// Go menjalankan fn di goroutine latar belakang yang ditunggu oleh Wait saat shutdown
+func Go(fn func()) {
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		fn()
+	}()
+}
+
+// Wait menunggu semua goroutine yang dimulai lewat Go selesai, atau sampai ctx berakhir
+func Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
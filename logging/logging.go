@@ -0,0 +1,116 @@
+// Package logging mengatur log/slog sebagai logger aplikasi dan menyisipkan
+// request ID serta trace ID dari context ke setiap baris log.
+package logging
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/tracing"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+)
+
+// Format output log yang didukung
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Configure memasang slog sebagai logger bawaan dengan format (json atau text) dan
+// level minimal (debug, info, warn, error). Pemanggilan log.Printf yang tersisa ikut
+// diteruskan ke slog pada level info.
+func Configure(w io.Writer, format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("level log %q tidak dikenal", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("format log %q tidak dikenal", format)
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	// slog.SetDefault mengarahkan paket log ke handler; prefix waktu sudah ditulis slog
+	log.SetFlags(0)
+	return nil
+}
+
+// contextHandler menambahkan request_id, principal (pengguna atau API key yang
+// terautentikasi), trace_id, dan span_id dari context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		r.AddAttrs(slog.String("principal", p.ID()), slog.String("principal_name", p.Name()))
+	}
+	if span := tracing.SpanFromContext(ctx); span != nil {
+		r.AddAttrs(
+			slog.String("trace_id", span.Context.TraceID.String()),
+			slog.String("span_id", span.Context.SpanID.String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// RequestIDHeader adalah header yang membawa request ID
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength membatasi request ID dari klien agar tidak membanjiri log
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// WithRequestID menyimpan request ID di context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID mengembalikan request ID dari context, atau string kosong
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID membuat request ID acak 128-bit dalam bentuk hex
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ValidRequestID melaporkan apakah request ID dari klien aman diteruskan ke log dan header:
+// tidak kosong, tidak terlalu panjang, dan hanya berisi huruf, angka, serta - _ . :
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '-' || r == '_' || r == '.' || r == ':')
+	}) < 0
+}
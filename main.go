@@ -1,15 +1,31 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/changefeed"
 	"crud-buku-go/config"
+	"crud-buku-go/lifecycle"
+	"crud-buku-go/logging"
 	"crud-buku-go/models"
+	"crud-buku-go/oidc"
+	"crud-buku-go/ratelimit"
 	"crud-buku-go/routes"
+	"crud-buku-go/search"
+	"crud-buku-go/tracing"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
-	"github.com/joho/godotenv"
 	_ "crud-buku-go/docs"
 )
 
@@ -28,35 +44,78 @@ import (
 // @host localhost:8080
 // @BasePath /api
 // @schemes http
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Token akses dari /auth/login dengan format "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description API key dari /admin/api-keys dengan format "ApiKey <key>"
 func main() {
-	err := godotenv.Load()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "provision":
+			runProvision(os.Args[2:])
+			return
+		case "create-user":
+			runCreateUser(os.Args[2:])
+			return
+		}
+	}
+
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		log.Println("Peringatan: Tidak dapat memuat file .env. Pastikan variabel environment sudah diatur.")
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
 	}
 
-	config.ConnectDB()
-	defer func() {
-		if config.DB != nil {
-			config.DB.Close()
-			log.Println("Koneksi database ditutup.")
+	if err := logging.Configure(os.Stderr, cfg.Log.Format, cfg.Log.Level); err != nil {
+		log.Fatalf("Gagal mengatur logging: %v", err)
+	}
+
+	if err := tracing.Configure(cfg.Tracing.Exporter, cfg.Tracing.File, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio); err != nil {
+		log.Fatalf("Gagal mengatur tracing: %v", err)
+	}
+
+	if err := auth.Configure(cfg.Auth); err != nil {
+		log.Fatalf("Gagal mengatur autentikasi: %v", err)
+	}
+	if cfg.OIDC.Enabled() {
+		if err := oidc.Configure(cfg.OIDC); err != nil {
+			log.Fatalf("Gagal mengatur login OIDC: %v", err)
 		}
-	}()
+	}
 
+	config.ConnectDB()
+
+	models.ConfigureStore()
 	models.SeedData() //
 
-	router := routes.SetupRoutes() //
+	if err := search.Configure(); err != nil {
+		log.Fatalf("Gagal mengatur backend pencarian: %v", err)
+	}
+
+	if err := ratelimit.Configure(cfg.RateLimit); err != nil {
+		log.Fatalf("Gagal mengatur rate limit: %v", err)
+	}
 
-	appPort := os.Getenv("APP_PORT")
-	if appPort == "" {
-		appPort = "8080" // Port default
+	var listener *changefeed.Listener
+	if cfg.Database.Driver == config.DriverPostgres && cfg.Database.ListenChanges {
+		listener, err = changefeed.Start(config.DSN)
+		if err != nil {
+			log.Fatalf("Gagal memulai listener perubahan buku: %v", err)
+		}
 	}
 
+	router := routes.SetupRoutes() //
+
+	appPort := strconv.Itoa(cfg.App.Port)
+
 	serverAddr := fmt.Sprintf("0.0.0.0:%s", appPort)
 
-	log.Printf("📚 Dokumentasi API (Swagger UI) tersedia di http://localhost:%s/api/doc/", appPort)
-	log.Printf("🚀 Server berjalan di http://localhost:%s", appPort)
-	log.Printf("🌐 Server juga dapat diakses di LAN pada alamat IP mesin Anda dengan port %s", appPort)
-	log.Printf("Tekan CTRL+C untuk menghentikan server.")
+	slog.Info("Dokumentasi API (Swagger UI) tersedia", "url", "http://localhost:"+appPort+"/api/doc/")
 
 	server := &http.Server{
 		Addr:         serverAddr,
@@ -66,12 +125,159 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("🚀 Server berjalan di %s", serverAddr)
-	err = server.ListenAndServe()
-	if err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Gagal menjalankan server: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server berjalan", "addr", serverAddr, "url", "http://localhost:"+appPort)
+		serverErr <- server.ListenAndServe()
+	}()
+	lifecycle.SetReady(true)
+
+	failed := false
+	select {
+	case err := <-serverErr:
+		if err != nil && err != http.ErrServerClosed {
+			slog.Error("Gagal menjalankan server", "error", err)
+			failed = true
+		}
+	case <-ctx.Done():
+		slog.Info("Sinyal berhenti diterima, memulai shutdown")
+	}
+	// Sinyal kedua menghentikan proses seketika
+	stop()
+
+	shutdown(server, listener, cfg.App)
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// shutdown menghentikan aplikasi secara bertahap: readiness dibuat gagal lebih dulu,
+// lalu server berhenti menerima koneksi dan menunggu request yang sedang berjalan,
+// kemudian goroutine latar belakang ditunggu sebelum pool database ditutup.
+func shutdown(server *http.Server, listener *changefeed.Listener, app config.AppConfig) {
+	lifecycle.SetReady(false)
+	if app.ShutdownDelay > 0 {
+		slog.Info("Readiness dinonaktifkan, menunggu sebelum berhenti menerima koneksi", "delay", app.ShutdownDelay)
+		time.Sleep(app.ShutdownDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), app.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("Request belum selesai saat batas waktu shutdown habis", "error", err)
+	} else {
+		slog.Info("Semua request selesai diproses")
+	}
+
+	if listener != nil {
+		listener.Close()
+	}
+
+	if err := lifecycle.Wait(ctx); err != nil {
+		slog.Warn("Goroutine latar belakang belum selesai saat batas waktu shutdown habis", "error", err)
+	}
+
+	if config.DB != nil {
+		config.DB.Close()
+		slog.Info("Koneksi database ditutup")
+	}
+	if err := tracing.Shutdown(); err != nil {
+		slog.Error("Gagal menutup exporter trace", "error", err)
+	}
+	slog.Info("Server berhenti")
+}
+
+// runProvision menjalankan perintah `provision`: membuat role, database, dan
+// ekstensi yang dibutuhkan aplikasi, lalu keluar tanpa menjalankan server.
+func runProvision(args []string) {
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
+	}
+	if err := logging.Configure(os.Stderr, cfg.Log.Format, cfg.Log.Level); err != nil {
+		log.Fatalf("Gagal mengatur logging: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.App.Database.ConnectDeadline)
+	defer cancel()
+
+	if err := config.Provision(ctx); err != nil {
+		log.Fatalf("Provisioning gagal: %v", err)
 	}
 }
 
+// runCreateUser menjalankan perintah `create-user <username> [peran]`: membuat pengguna baru
+// (peran bawaan reader) atau mengganti password, dan peran jika disebutkan, milik pengguna
+// yang sudah ada. Password dibaca dari baris pertama stdin agar tidak tercatat di riwayat
+// shell, misalnya: echo "$PASSWORD" | go run . create-user admin admin
+func runCreateUser(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		log.Fatal("Pemakaian: create-user <username> [reader|librarian|admin] [flag konfigurasi] < password")
+	}
+	username := strings.ToLower(strings.TrimSpace(args[0]))
+	args = args[1:]
+
+	var role auth.Role
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		parsed, err := auth.ParseRole(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		role, args = parsed, args[1:]
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
+	}
+	if err := logging.Configure(os.Stderr, cfg.Log.Format, cfg.Log.Level); err != nil {
+		log.Fatalf("Gagal mengatur logging: %v", err)
+	}
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		log.Fatalf("Gagal membaca password dari stdin: %v", err)
+	}
+	hash, err := auth.HashPassword(strings.TrimRight(password, "\r\n"))
+	if err != nil {
+		log.Fatalf("Password tidak valid: %v", err)
+	}
+
+	config.ConnectDB()
+	defer config.DB.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.App.Database.ConnectDeadline)
+	defer cancel()
+
+	user, err := models.GetUserByUsername(ctx, username)
+	switch {
+	case err == nil:
+		if err := models.UpdateUserPassword(ctx, user.ID, hash); err != nil {
+			log.Fatalf("Gagal memperbarui password: %v", err)
+		}
+		if role != "" {
+			if err := models.UpdateUserRole(ctx, user.ID, string(role)); err != nil {
+				log.Fatalf("Gagal memperbarui peran: %v", err)
+			}
+			user.Role = string(role)
+		}
+		slog.Info("Pengguna diperbarui", "username", username, "id", user.ID, "role", user.Role)
+	case errors.Is(err, models.ErrUserNotFound):
+		if role == "" {
+			role = auth.RoleReader
+		}
+		user = models.User{Username: username, Role: string(role), PasswordHash: hash}
+		if err := models.CreateUser(ctx, &user); err != nil {
+			log.Fatalf("Gagal membuat pengguna: %v", err)
+		}
+		slog.Info("Pengguna dibuat", "username", username, "id", user.ID, "role", user.Role)
+	default:
+		log.Fatalf("Gagal mencari pengguna: %v", err)
+	}
+}
 
 //http://localhost:8080/api/doc/
\ No newline at end of file
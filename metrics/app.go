@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"crud-buku-go/config"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// Metrik HTTP, dicatat oleh middleware di paket routes. Label route berisi template
+// rute mux (misalnya /api/books/{id}), bukan URI mentah, agar kardinalitasnya terbatas.
+var (
+	HTTPRequests = NewCounterVec("http_requests_total",
+		"Jumlah request HTTP yang selesai diproses.", "method", "route", "status")
+	HTTPDuration = NewHistogramVec("http_request_duration_seconds",
+		"Lama pemrosesan request HTTP dalam detik.", DefaultBuckets, "method", "route")
+	HTTPResponseSize = NewCounterVec("http_response_size_bytes_total",
+		"Total ukuran body response HTTP dalam byte.", "method", "route")
+	HTTPRateLimited = NewCounterVec("http_rate_limited_total",
+		"Jumlah request yang ditolak rate limit (429), per kelas rute.", "class")
+)
+
+// Metrik bisnis
+var (
+	BooksCreated = NewCounter("books_created_total", "Jumlah buku yang berhasil dibuat.")
+	BooksUpdated = NewCounter("books_updated_total", "Jumlah buku yang berhasil diperbarui.")
+	BooksDeleted = NewCounter("books_deleted_total", "Jumlah buku yang berhasil dihapus.")
+	Searches     = NewCounterVec("book_searches_total",
+		"Jumlah pencarian buku yang berhasil, per backend dan mode.", "backend", "mode")
+	ZeroResultSearches = NewCounterVec("book_searches_zero_results_total",
+		"Jumlah pencarian buku tanpa hasil, per backend dan mode.", "backend", "mode")
+)
+
+// Gauge pool koneksi database, dibaca dari sql.DBStats saat di-scrape
+func init() {
+	stat := func(fn func(s sql.DBStats) float64) func() float64 {
+		return func() float64 {
+			if config.DB == nil {
+				return 0
+			}
+			return fn(config.DB.Stats())
+		}
+	}
+
+	NewGaugeFunc("db_pool_max_open_connections", "Batas maksimal koneksi database terbuka.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	NewGaugeFunc("db_pool_open_connections", "Jumlah koneksi database terbuka.",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	NewGaugeFunc("db_pool_in_use_connections", "Jumlah koneksi database yang sedang dipakai.",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	NewGaugeFunc("db_pool_idle_connections", "Jumlah koneksi database idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	NewCounterFunc("db_pool_wait_count_total", "Jumlah total request koneksi yang harus menunggu.",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	NewCounterFunc("db_pool_wait_duration_seconds_total", "Total waktu menunggu koneksi database dalam detik.",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	NewCounterFunc("db_pool_max_idle_closed_total", "Jumlah koneksi yang ditutup karena batas koneksi idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	NewCounterFunc("db_pool_max_idle_time_closed_total", "Jumlah koneksi yang ditutup karena batas waktu idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }))
+	NewCounterFunc("db_pool_max_lifetime_closed_total", "Jumlah koneksi yang ditutup karena batas umur koneksi.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
+
+// ObserveRequest mencatat satu request HTTP yang sudah selesai
+func ObserveRequest(method, route string, status, size int, duration time.Duration) {
+	HTTPRequests.Inc(method, route, strconv.Itoa(status))
+	HTTPDuration.Observe(duration.Seconds(), method, route)
+	HTTPResponseSize.Add(float64(size), method, route)
+}
+
+// ObserveSearch mencatat satu pencarian buku yang berhasil beserta jumlah hasilnya
+func ObserveSearch(backend, mode string, results int) {
+	Searches.Inc(backend, mode)
+	if results == 0 {
+		ZeroResultSearches.Inc(backend, mode)
+	}
+}
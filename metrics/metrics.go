@@ -0,0 +1,292 @@
+// Package metrics menyediakan counter, gauge, dan histogram sederhana beserta
+// eksposisinya dalam format teks Prometheus (versi 0.0.4), tanpa dependensi luar.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector adalah satu metrik yang bisa dituliskan ke eksposisi
+type collector interface {
+	describe() (name, help, kind string)
+	write(w *bufio.Writer)
+}
+
+// Registry menyimpan metrik dan menuliskannya dalam urutan pendaftaran
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// NewRegistry membuat registry kosong
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Default adalah registry yang dipakai oleh konstruktor paket dan Handler
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	name, _, _ := c.describe()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: metrik " + name + " didaftarkan dua kali")
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteTo menuliskan semua metrik dalam format teks Prometheus
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range collectors {
+		name, help, kind := c.describe()
+		fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, kind)
+		c.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler mengembalikan handler HTTP yang menyajikan registry Default
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Default.WriteTo(w)
+	})
+}
+
+// CounterVec adalah counter yang dipisah berdasarkan nilai label
+type CounterVec struct {
+	name, help string
+	labels     []string
+	mu         sync.Mutex
+	values     map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounter membuat counter tanpa label di registry Default
+func NewCounter(name, help string) *Counter {
+	return &Counter{vec: NewCounterVec(name, help)}
+}
+
+// NewCounterVec membuat counter berlabel di registry Default
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*series)}
+	Default.register(c)
+	return c
+}
+
+// Counter adalah counter tanpa label
+type Counter struct {
+	vec *CounterVec
+}
+
+// Inc menambah counter sebesar 1
+func (c *Counter) Inc() {
+	c.vec.Add(1)
+}
+
+// Inc menambah counter untuk kombinasi labelValues sebesar 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add menambah counter untuk kombinasi labelValues; nilai negatif diabaikan
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := seriesKey(c.name, c.labels, labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = s
+	}
+	s.value += v
+}
+
+func (c *CounterVec) describe() (string, string, string) { return c.name, c.help, "counter" }
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+		return
+	}
+	for _, key := range sortedKeys(c.values) {
+		s := c.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, s.labelValues, "", ""), formatFloat(s.value))
+	}
+}
+
+// funcMetric adalah gauge atau counter yang nilainya dibaca saat eksposisi
+type funcMetric struct {
+	name, help, kind string
+	fn               func() float64
+}
+
+// NewGaugeFunc mendaftarkan gauge yang nilainya diambil dari fn setiap kali di-scrape
+func NewGaugeFunc(name, help string, fn func() float64) {
+	Default.register(&funcMetric{name: name, help: help, kind: "gauge", fn: fn})
+}
+
+// NewCounterFunc mendaftarkan counter yang nilainya (selalu naik) diambil dari fn
+func NewCounterFunc(name, help string, fn func() float64) {
+	Default.register(&funcMetric{name: name, help: help, kind: "counter", fn: fn})
+}
+
+func (m *funcMetric) describe() (string, string, string) { return m.name, m.help, m.kind }
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	fmt.Fprintf(w, "%s %s\n", m.name, formatFloat(m.fn()))
+}
+
+// DefaultBuckets adalah batas histogram latensi dalam detik
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HistogramVec adalah histogram yang dipisah berdasarkan nilai label
+type HistogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+	mu         sync.Mutex
+	values     map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // jumlah per bucket (tidak kumulatif); dikumulasikan saat ditulis
+	sum         float64
+	count       uint64
+}
+
+// NewHistogramVec membuat histogram berlabel di registry Default; buckets harus terurut naik
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, values: make(map[string]*histogramSeries)}
+	Default.register(h)
+	return h
+}
+
+// Observe mencatat satu nilai untuk kombinasi labelValues
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := seriesKey(h.name, h.labels, labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.values[key]
+	if !ok {
+		s = &histogramSeries{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = s
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *HistogramVec) describe() (string, string, string) { return h.name, h.help, "histogram" }
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.values) {
+		s := h.values[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, s.labelValues, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, s.labelValues, "", ""), s.count)
+	}
+}
+
+func seriesKey(name string, labels, values []string) string {
+	if len(labels) != len(values) {
+		panic(fmt.Sprintf("metrics: %s membutuhkan %d label, diberikan %d", name, len(labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels menulis {a="x",b="y"}; extraName/extraValue dipakai untuk label le histogram
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	parts := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		parts = append(parts, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		parts = append(parts, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
+func escapeHelp(v string) string  { return helpEscaper.Replace(v) }
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,6 @@
+-- Enable trigram matching for typo-tolerant (fuzzy) search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- GIN trigram indexes so the word similarity operators (<%) can use an index
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_author_trgm ON books USING GIN (author gin_trgm_ops);
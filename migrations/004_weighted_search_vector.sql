@@ -0,0 +1,16 @@
+-- Weight the full-text vector per field so ts_rank can boost title and author matches:
+-- A = title, B = author, D = year (C is left unused)
+CREATE OR REPLACE FUNCTION books_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector =
+        setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.author, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(NEW.year::text, '')), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+UPDATE books SET search_vector =
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(author, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(year::text, '')), 'D');
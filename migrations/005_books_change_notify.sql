@@ -0,0 +1,17 @@
+-- Publish every change to the books table on the 'books_changed' channel so that
+-- each application instance can invalidate its in-memory caches and search indexes.
+-- Payload: {"op": "INSERT" | "UPDATE" | "DELETE", "id": <book id>}
+CREATE OR REPLACE FUNCTION books_notify_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('books_changed', json_build_object(
+        'op', TG_OP,
+        'id', CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END
+    )::text);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS books_notify_change_trigger ON books;
+CREATE TRIGGER books_notify_change_trigger
+AFTER INSERT OR UPDATE OR DELETE ON books
+FOR EACH ROW EXECUTE FUNCTION books_notify_change();
@@ -0,0 +1,11 @@
+// Package migrations menyimpan skema database. Migrasi PostgreSQL di direktori ini
+// dijalankan manual dengan psql; migrasi SQLite di sqlite/ disertakan ke dalam binary
+// dan dijalankan otomatis saat startup.
+package migrations
+
+import "embed"
+
+// SQLite berisi migrasi SQLite, diterapkan berurutan berdasarkan nama file
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
@@ -0,0 +1,27 @@
+-- Indeks FTS5 untuk pencarian full-text, memakai tabel books sebagai konten
+-- (external content) sehingga teks tidak disimpan dua kali
+CREATE VIRTUAL TABLE IF NOT EXISTS books_fts USING fts5(
+    title,
+    author,
+    year,
+    content = 'books',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+-- Trigger menjaga indeks tetap sinkron dengan tabel books
+CREATE TRIGGER IF NOT EXISTS books_fts_insert AFTER INSERT ON books BEGIN
+    INSERT INTO books_fts (rowid, title, author, year) VALUES (new.id, new.title, new.author, new.year);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_fts_delete AFTER DELETE ON books BEGIN
+    INSERT INTO books_fts (books_fts, rowid, title, author, year) VALUES ('delete', old.id, old.title, old.author, old.year);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_fts_update AFTER UPDATE ON books BEGIN
+    INSERT INTO books_fts (books_fts, rowid, title, author, year) VALUES ('delete', old.id, old.title, old.author, old.year);
+    INSERT INTO books_fts (rowid, title, author, year) VALUES (new.id, new.title, new.author, new.year);
+END;
+
+-- Isi indeks untuk baris yang sudah ada sebelum migrasi ini
+INSERT INTO books_fts (books_fts) VALUES ('rebuild');
@@ -0,0 +1,2 @@
+-- Peran pengguna untuk RBAC (reader, librarian, admin); pengguna lama menjadi reader
+ALTER TABLE users ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'reader';
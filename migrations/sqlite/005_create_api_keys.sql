@@ -0,0 +1,14 @@
+-- API key untuk klien mesin, setara dengan tabel yang dibuat config.createTable di PostgreSQL.
+-- Hanya hash SHA-256 dari key lengkap yang disimpan; key_id adalah bagian publik key.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    key_id VARCHAR(32) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
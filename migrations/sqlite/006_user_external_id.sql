@@ -0,0 +1,3 @@
+-- Pengenal pengguna di identity provider OIDC ("<issuer>|<sub>"); kosong untuk akun lokal
+ALTER TABLE users ADD COLUMN external_id VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_id ON users (external_id);
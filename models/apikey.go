@@ -0,0 +1,125 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// APIKey adalah kunci akses untuk klien mesin. Hanya hash dari key lengkap yang disimpan;
+// KeyID adalah bagian publik key yang dipakai untuk mencarinya.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	KeyID      string     `json:"key_id"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  int        `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active melaporkan apakah key belum dicabut dan belum kedaluwarsa pada waktu now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// ErrAPIKeyNotFound dikembalikan jika API key yang dicari tidak ada atau sudah dicabut
+var ErrAPIKeyNotFound = &Error{Kind: ErrNotFound, Message: "API key tidak ditemukan"}
+
+const apiKeyColumns = "id, name, key_id, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at"
+
+// CreateAPIKey menyimpan API key baru; Hash harus sudah berisi hash key lengkap
+func CreateAPIKey(ctx context.Context, key *APIKey) error {
+	var createdBy sql.NullInt64
+	if key.CreatedBy != 0 {
+		createdBy = sql.NullInt64{Int64: int64(key.CreatedBy), Valid: true}
+	}
+	var expiresAt sql.NullTime
+	if key.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *key.ExpiresAt, Valid: true}
+	}
+	return db().QueryRowContext(ctx, `INSERT INTO api_keys (name, key_id, key_hash, scopes, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`,
+		key.Name, key.KeyID, key.Hash, strings.Join(key.Scopes, " "), createdBy, time.Now(), expiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+// ListAPIKeys mengambil semua API key, termasuk yang sudah dicabut, urut berdasarkan ID
+func ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	rows, err := db().QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByKeyID mengambil API key berdasarkan bagian publiknya
+func GetAPIKeyByKeyID(ctx context.Context, keyID string) (APIKey, error) {
+	return scanAPIKey(db().QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_id = $1", keyID))
+}
+
+// RevokeAPIKey mencabut API key; key yang sudah dicabut dianggap tidak ditemukan
+func RevokeAPIKey(ctx context.Context, id int) error {
+	result, err := db().ExecContext(ctx, "UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL",
+		time.Now(), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchAPIKey mencatat waktu terakhir API key dipakai
+func TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	_, err := db().ExecContext(ctx, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", usedAt, id)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (APIKey, error) {
+	var key APIKey
+	var scopes string
+	var createdBy sql.NullInt64
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.KeyID, &key.Hash, &scopes, &createdBy, &key.CreatedAt,
+		&expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return key, ErrAPIKeyNotFound
+		}
+		return key, err
+	}
+	key.Scopes = strings.Fields(scopes)
+	key.CreatedBy = int(createdBy.Int64)
+	key.ExpiresAt = nullTime(expiresAt)
+	key.LastUsedAt = nullTime(lastUsedAt)
+	key.RevokedAt = nullTime(revokedAt)
+	return key, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
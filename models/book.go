@@ -1,10 +1,12 @@
 package models
 
 import (
-	"crud-buku-go/config"
+	"context"
 	"database/sql"
 	"errors"
 	"log"
+	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -19,9 +21,20 @@ type Book struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Error yang dikembalikan store jika buku dengan ID yang diminta tidak ada; semuanya
+// berjenis ErrNotFound
+var (
+	errBookNotFound          = &Error{Kind: ErrNotFound, Message: "buku tidak ditemukan"}
+	errBookNotFoundForUpdate = &Error{Kind: ErrNotFound, Message: "buku tidak ditemukan untuk diperbarui"}
+	errBookNotFoundForDelete = &Error{Kind: ErrNotFound, Message: "buku tidak ditemukan untuk dihapus"}
+)
+
+// PostgresStore adalah BookStore yang membaca dan menulis langsung ke PostgreSQL lewat config.DB
+type PostgresStore struct{}
+
 // GetAllBooks mengambil semua buku dari database
-func GetAllBooks() ([]Book, error) {
-	rows, err := config.DB.Query("SELECT id, title, author, year, created_at, updated_at FROM books ORDER BY id ASC")
+func (PostgresStore) GetAllBooks(ctx context.Context) ([]Book, error) {
+	rows, err := db().QueryContext(ctx, "SELECT id, title, author, year, created_at, updated_at FROM books ORDER BY id ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -39,13 +52,13 @@ func GetAllBooks() ([]Book, error) {
 }
 
 // GetBookByID mengambil satu buku berdasarkan ID
-func GetBookByID(id int) (Book, error) {
+func (PostgresStore) GetBookByID(ctx context.Context, id int) (Book, error) {
 	var book Book
-	row := config.DB.QueryRow("SELECT id, title, author, year, created_at, updated_at FROM books WHERE id = $1", id)
+	row := db().QueryRowContext(ctx, "SELECT id, title, author, year, created_at, updated_at FROM books WHERE id = $1", id)
 	err := row.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return book, errors.New("buku tidak ditemukan")
+			return book, errBookNotFound
 		}
 		return book, err
 	}
@@ -53,19 +66,10 @@ func GetBookByID(id int) (Book, error) {
 }
 
 // CreateBook menambahkan buku baru ke database
-func CreateBook(book *Book) error {
-	// Menggunakan goroutine untuk logging (contoh sederhana)
-	// Dalam aplikasi nyata, ini bisa untuk tugas background yang lebih kompleks
-	go func(b *Book) {
-		log.Printf("Goroutine: Memulai proses pembuatan buku: %s", b.Title)
-		// Simulasi pekerjaan tambahan
-		time.Sleep(100 * time.Millisecond)
-		log.Printf("Goroutine: Selesai proses pembuatan buku: %s", b.Title)
-	}(book)
-
+func (PostgresStore) CreateBook(ctx context.Context, book *Book) error {
 	query := `INSERT INTO books (title, author, year, created_at, updated_at)
 	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
-	err := config.DB.QueryRow(query, book.Title, book.Author, book.Year, time.Now(), time.Now()).Scan(&book.ID, &book.CreatedAt, &book.UpdatedAt)
+	err := db().QueryRowContext(ctx, query, book.Title, book.Author, book.Year, time.Now(), time.Now()).Scan(&book.ID, &book.CreatedAt, &book.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -73,20 +77,13 @@ func CreateBook(book *Book) error {
 }
 
 // UpdateBook memperbarui data buku di database
-func UpdateBook(id int, book *Book) error {
-	// Menggunakan goroutine untuk logging pembaruan
-	go func(bookID int, b *Book) {
-		log.Printf("Goroutine: Memulai proses pembaruan buku ID %d: %s", bookID, b.Title)
-		time.Sleep(50 * time.Millisecond)
-		log.Printf("Goroutine: Selesai proses pembaruan buku ID %d", bookID)
-	}(id, book)
-
+func (PostgresStore) UpdateBook(ctx context.Context, id int, book *Book) error {
 	query := `UPDATE books SET title = $1, author = $2, year = $3, updated_at = $4
 	          WHERE id = $5 RETURNING updated_at`
-	err := config.DB.QueryRow(query, book.Title, book.Author, book.Year, time.Now(), id).Scan(&book.UpdatedAt)
+	err := db().QueryRowContext(ctx, query, book.Title, book.Author, book.Year, time.Now(), id).Scan(&book.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New("buku tidak ditemukan untuk diperbarui")
+			return errBookNotFoundForUpdate
 		}
 		return err
 	}
@@ -94,64 +91,102 @@ func UpdateBook(id int, book *Book) error {
 	return nil
 }
 
-// SearchBooks mencari buku berdasarkan query
-func SearchBooks(query string) ([]Book, error) {
-	searchQuery := "%" + query + "%"
-	
-	// First try full-text search
-	rows, err := config.DB.Query(`
-		SELECT id, title, author, year, created_at, updated_at 
-		FROM books 
-		WHERE search_vector @@ plainto_tsquery('english', $1)
-		ORDER BY ts_rank(search_vector, plainto_tsquery('english', $1)) DESC
-	`, query)
-	
-	if err != nil {
-		// Fallback to LIKE search if full-text search fails
-		rows, err = config.DB.Query(`
-			SELECT id, title, author, year, created_at, updated_at 
-			FROM books 
-			WHERE LOWER(title) LIKE LOWER($1) 
-			   OR LOWER(author) LIKE LOWER($1)
-			   OR year::TEXT LIKE $1
-			ORDER BY 
-				CASE 
-					WHEN LOWER(title) = LOWER($1) THEN 1
-					WHEN LOWER(title) LIKE LOWER($1) || '%' THEN 2
-					WHEN LOWER(title) LIKE '%' || LOWER($1) || '%' THEN 3
-					ELSE 4
-				END,
+// FullTextSearchBooks mencari buku dengan full-text search PostgreSQL. Semua kata query
+// harus cocok, masing-masing sebagai awalan kata di judul, penulis, atau tahun.
+// Hasil diurutkan memakai bobot per field dan faktor kebaruan dari relevance;
+// jika explain bernilai true, setiap hasil menyertakan rincian skornya.
+func FullTextSearchBooks(ctx context.Context, query string, relevance RelevanceConfig, explain bool) ([]SearchResult, error) {
+	rows, err := db().QueryContext(ctx, `
+		WITH q AS (SELECT to_tsquery('english', $1) AS query)
+		SELECT id, title, author, year, created_at, updated_at,
+			ts_rank($2::float4[], search_vector, q.query) AS text_rank,
+			ts_rank($2::float4[], setweight(to_tsvector('english', title), 'A'), q.query) AS title_rank,
+			ts_rank($2::float4[], setweight(to_tsvector('english', author), 'B'), q.query) AS author_rank,
+			1.0 / (1.0 + GREATEST(0, EXTRACT(YEAR FROM CURRENT_DATE) - COALESCE(year, 0)) / $3) AS recency
+		FROM books, q
+		WHERE search_vector @@ q.query
+		ORDER BY ts_rank($2::float4[], search_vector, q.query) *
+			(1 + $4 * (1.0 / (1.0 + GREATEST(0, EXTRACT(YEAR FROM CURRENT_DATE) - COALESCE(year, 0)) / $3))) DESC,
 			title
-		`, searchQuery)
-		
-		if err != nil {
+	`, tsPrefixQuery(query), relevance.weights(), relevance.RecencyScale, relevance.RecencyWeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var breakdown ScoreExplanation
+		if err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Year, &result.CreatedAt, &result.UpdatedAt,
+			&breakdown.TextRank, &breakdown.TitleRank, &breakdown.AuthorRank, &breakdown.Recency); err != nil {
 			return nil, err
 		}
+		breakdown.RecencyWeight = relevance.RecencyWeight
+		breakdown.Score = breakdown.TextRank * (1 + relevance.RecencyWeight*breakdown.Recency)
+		result.Score = breakdown.Score
+		if explain {
+			breakdown.Boosts = relevance.Boosts()
+			result.Explain = &breakdown
+		}
+		results = append(results, result)
 	}
+	return results, rows.Err()
+}
 
+// tsPrefixQuery mengubah input pengguna menjadi teks to_tsquery yang aman: setiap kata
+// dikutip sebagai lexeme dengan penanda awalan (:*) sehingga operator tsquery di input
+// tidak berlaku, lalu digabung dengan & seperti plainto_tsquery.
+func tsPrefixQuery(input string) string {
+	var terms []string
+	for _, word := range strings.Fields(input) {
+		terms = append(terms, "'"+tsqueryEscaper.Replace(word)+"':*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+var tsqueryEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// LikeSearchBooks mencari buku dengan pencocokan LIKE pada judul, penulis, dan tahun.
+// Tidak membutuhkan kolom search_vector sehingga bisa dipakai tanpa migrasi full-text,
+// dan SQL-nya berjalan di PostgreSQL maupun SQLite.
+func LikeSearchBooks(ctx context.Context, query string) ([]SearchResult, error) {
+	searchQuery := "%" + query + "%"
+
+	rows, err := db().QueryContext(ctx, `
+		SELECT id, title, author, year, created_at, updated_at 
+		FROM books 
+		WHERE LOWER(title) LIKE LOWER($1) 
+		   OR LOWER(author) LIKE LOWER($1)
+		   OR CAST(year AS TEXT) LIKE $1
+		ORDER BY 
+			CASE 
+				WHEN LOWER(title) = LOWER($1) THEN 1
+				WHEN LOWER(title) LIKE LOWER($1) || '%' THEN 2
+				WHEN LOWER(title) LIKE '%' || LOWER($1) || '%' THEN 3
+				ELSE 4
+			END,
+		title
+	`, searchQuery)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
-	var books []Book
+	var results []SearchResult
 	for rows.Next() {
-		var book Book
-		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt); err != nil {
+		var result SearchResult
+		if err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Year, &result.CreatedAt, &result.UpdatedAt); err != nil {
 			return nil, err
 		}
-		books = append(books, book)
+		results = append(results, result)
 	}
-	return books, nil
+	return results, rows.Err()
 }
 
 // DeleteBook menghapus buku dari database
-func DeleteBook(id int) error {
-	// Menggunakan goroutine untuk logging penghapusan
-	go func(bookID int) {
-		log.Printf("Goroutine: Memulai proses penghapusan buku ID %d", bookID)
-		time.Sleep(50 * time.Millisecond)
-		log.Printf("Goroutine: Selesai proses penghapusan buku ID %d", bookID)
-	}(id)
-
-	result, err := config.DB.Exec("DELETE FROM books WHERE id = $1", id)
+func (PostgresStore) DeleteBook(ctx context.Context, id int) error {
+	result, err := db().ExecContext(ctx, "DELETE FROM books WHERE id = $1", id)
 	if err != nil {
 		return err
 	}
@@ -160,7 +195,7 @@ func DeleteBook(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("buku tidak ditemukan untuk dihapus")
+		return errBookNotFoundForDelete
 	}
 	return nil
 }
@@ -168,17 +203,17 @@ func DeleteBook(id int) error {
 // SeedData mengisi data dummy ke tabel buku jika kosong
 func SeedData() {
 	var count int
-	err := config.DB.QueryRow("SELECT COUNT(*) FROM books").Scan(&count)
+	err := db().QueryRowContext(context.Background(), "SELECT COUNT(*) FROM books").Scan(&count)
 	if err != nil {
 		log.Fatalf("Gagal menghitung data buku: %v", err)
 	}
 
 	if count > 0 {
-		log.Println("Data buku sudah ada, tidak perlu seeding.")
+		slog.Info("Data buku sudah ada, tidak perlu seeding", "count", count)
 		return
 	}
 
-	log.Println("Memulai seeding data buku...")
+	slog.Info("Memulai seeding data buku")
 
 	dummyBooks := []Book{
 		{Title: "Laskar Pelangi", Author: "Andrea Hirata", Year: 2005},
@@ -204,15 +239,20 @@ func SeedData() {
 		{Title: "Sirkus Pohon", Author: "Andrea Hirata", Year: 2017},
 	}
 
+	// Data dummy ditulis langsung lewat Store agar metrik books_created_total hanya
+	// menghitung penulisan dari API; hook perubahan cukup dijalankan sekali di akhir.
+	seeded := 0
 	for _, book := range dummyBooks {
-		// Kita panggil CreateBook agar goroutine di dalamnya juga tereksekusi
-		// untuk setiap data dummy, meskipun ini hanya contoh sederhana.
-		err := CreateBook(&book) // Perhatikan, CreateBook mengembalikan ID, dll.
+		err := Store.CreateBook(context.Background(), &book)
 		if err != nil {
-			log.Printf("Gagal seeding buku '%s': %v", book.Title, err)
+			slog.Error("Gagal seeding buku", "title", book.Title, "error", err)
 		} else {
-			log.Printf("Berhasil seeding buku: %s (ID: %d)", book.Title, book.ID)
+			seeded++
+			slog.Debug("Berhasil seeding buku", "title", book.Title, "id", book.ID)
 		}
 	}
-	log.Println("Seeding data buku selesai.")
+	if seeded > 0 {
+		notifyBooksChanged()
+	}
+	slog.Info("Seeding data buku selesai", "count", seeded)
 }
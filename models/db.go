@@ -0,0 +1,96 @@
+package models
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crud-buku-go/tracing"
+	"database/sql"
+	"runtime"
+	"strings"
+)
+
+// queryer adalah bagian dari *sql.DB dan *sql.Tx yang dipakai paket ini
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// tracedQueryer mencatat setiap query sebagai span client, ditandai dengan operasi
+// SQL (SELECT, INSERT, ...) dan fungsi pemanggilnya. Span untuk Query berakhir saat
+// hasil pertama tersedia, bukan saat rows selesai dibaca.
+type tracedQueryer struct {
+	q queryer
+}
+
+// traced membungkus config.DB atau transaksi agar query-nya tercatat di trace
+func traced(q queryer) tracedQueryer {
+	return tracedQueryer{q: q}
+}
+
+// db mengembalikan koneksi database aplikasi yang sudah dibungkus tracing
+func db() tracedQueryer {
+	return traced(config.DB)
+}
+
+func (t tracedQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	rows, err := t.q.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+	return rows, err
+}
+
+func (t tracedQueryer) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	row := t.q.QueryRowContext(ctx, query, args...)
+	span.RecordError(row.Err())
+	return row
+}
+
+func (t tracedQueryer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+	result, err := t.q.ExecContext(ctx, query, args...)
+	span.RecordError(err)
+	return result, err
+}
+
+// startQuerySpan memulai span untuk satu query; nama span adalah operasi SQL-nya
+func startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	operation := sqlOperation(query)
+	ctx, span := tracing.Start(ctx, operation, tracing.KindClient)
+	if span == nil {
+		return ctx, nil
+	}
+
+	system := "postgresql"
+	if config.App.Database.Driver == config.DriverSQLite {
+		system = "sqlite"
+	}
+	span.SetAttribute("db.system", system)
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.statement", strings.Join(strings.Fields(query), " "))
+	// Lewati startQuerySpan dan method tracedQueryer untuk mendapatkan fungsi pemanggil
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			span.SetAttribute("code.function", strings.TrimPrefix(fn.Name(), "crud-buku-go/"))
+		}
+	}
+	return ctx, span
+}
+
+// sqlOperation mengembalikan kata kunci pertama query, misalnya SELECT.
+// Query yang diawali WITH dianggap SELECT.
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	operation := strings.ToUpper(fields[0])
+	if operation == "WITH" {
+		return "SELECT"
+	}
+	return operation
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Jenis kesalahan domain. Semua kesalahan dari paket ini yang bukan kesalahan internal
+// bisa dicocokkan dengan errors.Is terhadap salah satu nilai ini, tanpa membaca pesannya.
+var (
+	ErrNotFound    = errors.New("data tidak ditemukan")
+	ErrConflict    = errors.New("data bentrok dengan data lain")
+	ErrValidation  = errors.New("data tidak valid")
+	ErrUnavailable = errors.New("penyimpanan data sedang tidak tersedia")
+)
+
+// Error adalah kesalahan domain. Message aman ditampilkan ke klien, sedangkan Err adalah
+// penyebab internal (misalnya error driver database) yang hanya boleh masuk log.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap mengembalikan jenis kesalahan dan penyebabnya agar keduanya bisa dicocokkan
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// Classify mengubah error driver database menjadi kesalahan domain: pelanggaran
+// constraint menjadi ErrConflict, koneksi yang putus, database yang sibuk, atau batas
+// waktu yang habis menjadi ErrUnavailable. Kesalahan domain dan error lain dikembalikan
+// apa adanya.
+func Classify(err error) error {
+	var domain *Error
+	var validation *ValidationError
+	if err == nil || errors.As(err, &domain) || errors.As(err, &validation) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "23": // integrity_constraint_violation
+			return &Error{Kind: ErrConflict, Message: "data bentrok dengan data yang sudah ada", Err: err}
+		case "08", "53", "57": // connection_exception, insufficient_resources, operator_intervention
+			return unavailable(err)
+		}
+		return err
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_CONSTRAINT:
+			return &Error{Kind: ErrConflict, Message: "data bentrok dengan data yang sudah ada", Err: err}
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+			return unavailable(err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr) {
+		return unavailable(err)
+	}
+	return err
+}
+
+func unavailable(err error) error {
+	return &Error{Kind: ErrUnavailable, Message: "database tidak dapat dihubungi", Err: err}
+}
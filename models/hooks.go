@@ -0,0 +1,27 @@
+package models
+
+import "sync"
+
+var (
+	changeHooksMu sync.RWMutex
+	changeHooks   []func()
+)
+
+// OnBooksChanged mendaftarkan fungsi yang dipanggil setiap kali data buku
+// dibuat, diperbarui, atau dihapus, misalnya untuk membuang cache atau indeks di memori.
+func OnBooksChanged(fn func()) {
+	changeHooksMu.Lock()
+	defer changeHooksMu.Unlock()
+	changeHooks = append(changeHooks, fn)
+}
+
+// notifyBooksChanged menjalankan semua hook perubahan data buku
+func notifyBooksChanged() {
+	changeHooksMu.RLock()
+	hooks := append([]func(){}, changeHooks...)
+	changeHooksMu.RUnlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+// SearchResult merepresentasikan satu buku hasil pencarian beserta skornya
+type SearchResult struct {
+	Book
+	Score   float64           `json:"score,omitempty"`
+	Explain *ScoreExplanation `json:"explain,omitempty"`
+}
+
+// ScoreExplanation merinci komponen skor satu hasil pencarian full-text.
+// Score = TextRank * (1 + RecencyWeight * Recency).
+type ScoreExplanation struct {
+	TextRank      float64            `json:"text_rank"`
+	TitleRank     float64            `json:"title_rank"`
+	AuthorRank    float64            `json:"author_rank"`
+	Recency       float64            `json:"recency"`
+	RecencyWeight float64            `json:"recency_weight"`
+	Boosts        map[string]float64 `json:"boosts"`
+	Score         float64            `json:"score"`
+}
+
+// RelevanceConfig mengatur bobot per field dan faktor kebaruan untuk peringkat full-text.
+// Recency bernilai 1 untuk buku tahun ini dan turun menjadi 0.5 setelah RecencyScale tahun.
+type RelevanceConfig struct {
+	TitleBoost    float64
+	AuthorBoost   float64
+	YearBoost     float64
+	RecencyWeight float64
+	RecencyScale  float64
+}
+
+// LoadRelevanceConfig mengambil bobot relevansi dari konfigurasi pencarian
+func LoadRelevanceConfig() RelevanceConfig {
+	search := config.App.Search
+	return RelevanceConfig{
+		TitleBoost:    search.BoostTitle,
+		AuthorBoost:   search.BoostAuthor,
+		YearBoost:     search.BoostYear,
+		RecencyWeight: search.RecencyWeight,
+		RecencyScale:  search.RecencyScale,
+	}
+}
+
+// weights mengembalikan array bobot ts_rank dengan urutan {D, C, B, A}
+func (c RelevanceConfig) weights() string {
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	return "{" + format(c.YearBoost) + ",0," + format(c.AuthorBoost) + "," + format(c.TitleBoost) + "}"
+}
+
+// Boosts mengembalikan bobot per field dalam bentuk map untuk ditampilkan di explain
+func (c RelevanceConfig) Boosts() map[string]float64 {
+	return map[string]float64{"title": c.TitleBoost, "author": c.AuthorBoost, "year": c.YearBoost}
+}
+
+// @Description Hasil pencarian buku
+// SearchResponse adalah payload response untuk endpoint pencarian
+type SearchResponse struct {
+	Query      string         `json:"query"`
+	Mode       string         `json:"mode"`
+	Backend    string         `json:"backend"`
+	Threshold  float64        `json:"threshold,omitempty"`
+	Results    []SearchResult `json:"results"`
+	DidYouMean string         `json:"did_you_mean,omitempty"`
+}
+
+// FuzzyThreshold mengembalikan ambang kemiripan trigram dari konfigurasi pencarian
+func FuzzyThreshold() float64 {
+	return config.App.Search.FuzzyThreshold
+}
+
+// TrigramAvailable melaporkan apakah pencarian fuzzy dan "did you mean" (pg_trgm)
+// bisa dipakai, yaitu hanya jika database-nya PostgreSQL
+func TrigramAvailable() bool {
+	return config.App.Database.Driver == config.DriverPostgres
+}
+
+// FuzzySearchBooks mencari buku berdasarkan kemiripan trigram pada judul dan penulis.
+// Threshold dipasang per transaksi agar operator <% tetap bisa memakai indeks GIN trigram.
+func FuzzySearchBooks(ctx context.Context, query string, threshold float64) ([]SearchResult, error) {
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = traced(tx).ExecContext(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)",
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := traced(tx).QueryContext(ctx, `
+		SELECT id, title, author, year, created_at, updated_at,
+			GREATEST(word_similarity($1, title), word_similarity($1, author)) AS score
+		FROM books
+		WHERE $1 <% title OR $1 <% author
+		ORDER BY score DESC, title
+	`, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		if err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Year,
+			&result.CreatedAt, &result.UpdatedAt, &result.Score); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// SuggestSearchTerm mencari judul atau penulis yang paling mirip dengan query
+// untuk ditawarkan sebagai "did you mean". Mengembalikan string kosong jika tidak ada.
+func SuggestSearchTerm(ctx context.Context, query string) (string, error) {
+	var term string
+	err := db().QueryRowContext(ctx, `
+		SELECT term
+		FROM (SELECT title AS term FROM books UNION SELECT author FROM books) AS terms
+		WHERE similarity(term, $1) > 0
+		ORDER BY similarity(term, $1) DESC, term
+		LIMIT 1
+	`, query).Scan(&term)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return term, nil
+}
+
+// QueryBooks menjalankan klausa WHERE hasil kompilasi searchql beserta parameternya.
+// Klausa harus berasal dari searchql.Compile, bukan dari input pengguna secara langsung.
+func QueryBooks(ctx context.Context, where string, args []interface{}) ([]Book, error) {
+	rows, err := db().QueryContext(ctx, `
+		SELECT id, title, author, year, created_at, updated_at
+		FROM books
+		WHERE `+where+`
+		ORDER BY title, id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []Book
+	for rows.Next() {
+		var book Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SQLiteStore adalah BookStore yang membaca dan menulis ke SQLite lewat config.DB.
+// Skemanya berasal dari migrasi di migrations/sqlite.
+type SQLiteStore struct{}
+
+// GetAllBooks mengambil semua buku dari database
+func (SQLiteStore) GetAllBooks(ctx context.Context) ([]Book, error) {
+	rows, err := db().QueryContext(ctx, "SELECT id, title, author, year, created_at, updated_at FROM books ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []Book
+	for rows.Next() {
+		var book Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
+
+// GetBookByID mengambil satu buku berdasarkan ID
+func (SQLiteStore) GetBookByID(ctx context.Context, id int) (Book, error) {
+	var book Book
+	row := db().QueryRowContext(ctx, "SELECT id, title, author, year, created_at, updated_at FROM books WHERE id = ?", id)
+	err := row.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return book, errBookNotFound
+		}
+		return book, err
+	}
+	return book, nil
+}
+
+// CreateBook menambahkan buku baru ke database
+func (SQLiteStore) CreateBook(ctx context.Context, book *Book) error {
+	now := time.Now()
+	result, err := db().ExecContext(ctx, `INSERT INTO books (title, author, year, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?)`, book.Title, book.Author, book.Year, now, now)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	book.ID = int(id)
+	book.CreatedAt = now
+	book.UpdatedAt = now
+	return nil
+}
+
+// UpdateBook memperbarui data buku di database
+func (SQLiteStore) UpdateBook(ctx context.Context, id int, book *Book) error {
+	now := time.Now()
+	result, err := db().ExecContext(ctx, `UPDATE books SET title = ?, author = ?, year = ?, updated_at = ?
+	          WHERE id = ?`, book.Title, book.Author, book.Year, now, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errBookNotFoundForUpdate
+	}
+	book.ID = id
+	book.UpdatedAt = now
+	return nil
+}
+
+// DeleteBook menghapus buku dari database
+func (SQLiteStore) DeleteBook(ctx context.Context, id int) error {
+	result, err := db().ExecContext(ctx, "DELETE FROM books WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errBookNotFoundForDelete
+	}
+	return nil
+}
+
+// FTS5SearchBooks mencari buku lewat indeks FTS5 SQLite (migrasi 002_books_fts).
+// Peringkat memakai bm25 dengan bobot per field dari relevance, lalu dikalikan
+// faktor kebaruan dengan rumus yang sama seperti FullTextSearchBooks.
+func FTS5SearchBooks(ctx context.Context, query string, relevance RelevanceConfig, explain bool) ([]SearchResult, error) {
+	match := fts5Query(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	// bm25 bernilai negatif (lebih kecil lebih relevan), jadi tandanya dibalik
+	rows, err := db().QueryContext(ctx, `
+		SELECT b.id, b.title, b.author, b.year, b.created_at, b.updated_at,
+			-bm25(books_fts, ?, ?, ?) AS text_rank,
+			-bm25(books_fts, ?, 0, 0) AS title_rank,
+			-bm25(books_fts, 0, ?, 0) AS author_rank
+		FROM books_fts
+		JOIN books b ON b.id = books_fts.rowid
+		WHERE books_fts MATCH ?
+	`, relevance.TitleBoost, relevance.AuthorBoost, relevance.YearBoost,
+		relevance.TitleBoost, relevance.AuthorBoost, match)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	currentYear := float64(time.Now().Year())
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var breakdown ScoreExplanation
+		if err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Year, &result.CreatedAt, &result.UpdatedAt,
+			&breakdown.TextRank, &breakdown.TitleRank, &breakdown.AuthorRank); err != nil {
+			return nil, err
+		}
+		breakdown.Recency = 1 / (1 + math.Max(0, currentYear-float64(result.Year))/relevance.RecencyScale)
+		breakdown.RecencyWeight = relevance.RecencyWeight
+		breakdown.Score = breakdown.TextRank * (1 + relevance.RecencyWeight*breakdown.Recency)
+		result.Score = breakdown.Score
+		if explain {
+			breakdown.Boosts = relevance.Boosts()
+			result.Explain = &breakdown
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Title < results[j].Title
+	})
+	return results, nil
+}
+
+// fts5Query mengubah input pengguna menjadi query FTS5 yang aman: setiap kata
+// dikutip sebagai string sehingga operator FTS5 (AND, NEAR, *, kolom:) tidak berlaku,
+// semua kata harus cocok, dan masing-masing cocok sebagai awalan seperti tsPrefixQuery.
+func fts5Query(input string) string {
+	var terms []string
+	for _, word := range strings.Fields(input) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
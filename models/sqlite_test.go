@@ -0,0 +1,155 @@
+package models_test
+
+import (
+	"bytes"
+	"context"
+	"crud-buku-go/cache"
+	"crud-buku-go/config"
+	"crud-buku-go/metrics"
+	"crud-buku-go/models"
+	"crud-buku-go/models/storetest"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// testPostgresDSNEnv berisi DSN PostgreSQL untuk uji store; uji Postgres dilewati jika kosong
+const testPostgresDSNEnv = "TEST_DATABASE_URL"
+
+// relevance adalah bobot pencarian uji; tanpa faktor kebaruan agar urutan hanya
+// ditentukan oleh field yang cocok
+var relevance = models.RelevanceConfig{TitleBoost: 1, AuthorBoost: 0.4, YearBoost: 0.1, RecencyScale: 10}
+
+func fts5Search(ctx context.Context, query string) ([]models.SearchResult, error) {
+	return models.FTS5SearchBooks(ctx, query, relevance, false)
+}
+
+func postgresSearch(ctx context.Context, query string) ([]models.SearchResult, error) {
+	return models.FullTextSearchBooks(ctx, query, relevance, false)
+}
+
+// useDB memasang db sebagai config.DB selama test berjalan
+func useDB(t *testing.T, db *sql.DB) {
+	t.Helper()
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		db.Close()
+	})
+}
+
+// openSQLite membuka database SQLite baru di direktori sementara lalu menerapkan migrasinya
+func openSQLite(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", config.SQLiteConnString(filepath.Join(t.TempDir(), "books.db")))
+	if err != nil {
+		t.Fatalf("membuka SQLite: %v", err)
+	}
+	if err := config.MigrateSQLite(context.Background(), db); err != nil {
+		db.Close()
+		t.Fatalf("migrasi SQLite: %v", err)
+	}
+	useDB(t, db)
+}
+
+func TestSQLiteStore(t *testing.T) {
+	openSQLite(t)
+	if err := storetest.Run(context.Background(), models.SQLiteStore{}, fts5Search); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachedSQLiteStore(t *testing.T) {
+	openSQLite(t)
+	store := models.NewCachedStore(models.SQLiteStore{}, cache.NewLRU(100, time.Minute), time.Minute)
+	if err := storetest.Run(context.Background(), store, fts5Search); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// booksCreated membaca nilai books_created_total dari registry metrik
+func booksCreated(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := metrics.Default.WriteTo(&buf); err != nil {
+		t.Fatalf("menulis metrik: %v", err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if value, ok := strings.CutPrefix(line, "books_created_total "); ok {
+			return value
+		}
+	}
+	t.Fatal("metrik books_created_total tidak ditemukan")
+	return ""
+}
+
+// Data dummy tidak boleh terhitung sebagai buku yang dibuat lewat API
+func TestSeedDataSkipsBookMetrics(t *testing.T) {
+	openSQLite(t)
+	previous := models.Store
+	models.Store = models.SQLiteStore{}
+	t.Cleanup(func() { models.Store = previous })
+
+	before := booksCreated(t)
+	models.SeedData()
+	if after := booksCreated(t); after != before {
+		t.Errorf("books_created_total berubah dari %s menjadi %s setelah seeding", before, after)
+	}
+	books, err := models.GetAllBooks(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllBooks: %v", err)
+	}
+	if len(books) == 0 {
+		t.Error("seeding tidak menambahkan buku")
+	}
+}
+
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv(testPostgresDSNEnv)
+	if dsn == "" {
+		t.Skip(testPostgresDSNEnv + " tidak diisi")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("membuka PostgreSQL: %v", err)
+	}
+	useDB(t, db)
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS books (
+		id SERIAL PRIMARY KEY,
+		title VARCHAR(255) NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		year INT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("membuat tabel books: %v", err)
+	}
+	// Migrasi full-text PostgreSQL dijalankan manual, jadi terapkan di sini jika belum
+	var migrated bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_name = 'books' AND column_name = 'search_vector')`).Scan(&migrated); err != nil {
+		t.Fatalf("memeriksa kolom search_vector: %v", err)
+	}
+	if !migrated {
+		for _, name := range []string{"002_add_search_indexes.sql", "004_weighted_search_vector.sql"} {
+			migration, err := os.ReadFile(filepath.Join("..", "migrations", name))
+			if err != nil {
+				t.Fatalf("membaca migrasi %s: %v", name, err)
+			}
+			if _, err := db.ExecContext(ctx, string(migration)); err != nil {
+				t.Fatalf("migrasi %s: %v", name, err)
+			}
+		}
+	}
+	if err := storetest.Run(ctx, models.PostgresStore{}, postgresSearch); err != nil {
+		t.Fatal(err)
+	}
+}
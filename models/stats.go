@@ -0,0 +1,214 @@
+package models
+
+import (
+	"context"
+	"crud-buku-go/cache"
+	"crud-buku-go/config"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CountByLabel adalah satu baris hasil agregasi (misalnya jumlah buku per penulis)
+type CountByLabel struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// YearBucket adalah satu batang histogram tahun terbit, inklusif dari From sampai To
+type YearBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// @Description Statistik katalog buku
+// CatalogStats berisi agregasi statistik katalog buku
+type CatalogStats struct {
+	TotalBooks     int            `json:"total_books"`
+	TotalAuthors   int            `json:"total_authors"`
+	BooksPerAuthor []CountByLabel `json:"books_per_author"`
+	TopAuthors     []CountByLabel `json:"top_authors"`
+	BooksPerDecade []CountByLabel `json:"books_per_decade"`
+	AddedPerMonth  []CountByLabel `json:"added_per_month"`
+	YearHistogram  []YearBucket   `json:"year_histogram"`
+	GeneratedAt    time.Time      `json:"generated_at"`
+}
+
+// StatsFilter membatasi buku yang dihitung. Where dan Args berasal dari searchql.Compile.
+type StatsFilter struct {
+	Where      string
+	Args       []interface{}
+	TopAuthors int
+	BucketSize int
+}
+
+// maxStatsCacheEntries membatasi jumlah kombinasi filter yang disimpan di cache statistik;
+// kunci berasal dari parameter klien sehingga jumlahnya tidak boleh dibiarkan tumbuh
+const maxStatsCacheEntries = 256
+
+// statsCache menyimpan hasil statistik per kombinasi filter dengan TTL singkat.
+// Entri yang paling lama tidak dipakai dibuang saat cache penuh. Seperti CachedStore,
+// generation naik setiap kali data buku berubah agar hasil agregasi yang dimulai sebelum
+// perubahan tidak disimpan kembali sebagai data basi.
+type statsCache struct {
+	mu         sync.Mutex
+	entries    cache.Cache
+	generation uint64
+}
+
+var catalogStatsCache = &statsCache{entries: cache.NewLRU(maxStatsCacheEntries, 0)}
+
+func init() {
+	OnBooksChanged(catalogStatsCache.purge)
+}
+
+// get juga mengembalikan generasi cache saat ini untuk diteruskan ke put
+func (c *statsCache) get(key string) (CatalogStats, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.entries.Get(key)
+	if !ok {
+		return CatalogStats{}, c.generation, false
+	}
+	return cached.(CatalogStats), c.generation, true
+}
+
+// put menyimpan stats hanya jika belum ada perubahan data sejak generation
+func (c *statsCache) put(key string, generation uint64, stats CatalogStats, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.entries.Set(key, stats, ttl)
+	}
+}
+
+func (c *statsCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries.Purge()
+}
+
+// StatsCacheTTL mengembalikan masa berlaku cache statistik dari konfigurasi
+func StatsCacheTTL() time.Duration {
+	return config.App.Cache.StatsTTL
+}
+
+// GetCatalogStats menghitung statistik katalog dengan agregasi SQL.
+// Hasil disimpan di cache selama StatsCacheTTL untuk setiap kombinasi filter;
+// cacheKey kosong berarti cache tidak dipakai.
+func GetCatalogStats(ctx context.Context, filter StatsFilter, cacheKey string) (CatalogStats, error) {
+	ttl := StatsCacheTTL()
+	useCache := cacheKey != "" && ttl > 0
+	var generation uint64
+	if useCache {
+		cached, current, ok := catalogStatsCache.get(cacheKey)
+		if ok {
+			return cached, nil
+		}
+		generation = current
+	}
+
+	stats, err := computeCatalogStats(ctx, filter)
+	if err != nil {
+		return stats, err
+	}
+
+	if useCache {
+		catalogStatsCache.put(cacheKey, generation, stats, ttl)
+	}
+	return stats, nil
+}
+
+func computeCatalogStats(ctx context.Context, filter StatsFilter) (CatalogStats, error) {
+	where := filter.Where
+	if where == "" {
+		where = "TRUE"
+	}
+	args := filter.Args
+	next := "$" + strconv.Itoa(len(args)+1)
+
+	stats := CatalogStats{GeneratedAt: time.Now()}
+
+	err := db().QueryRowContext(ctx,
+		`SELECT COUNT(*), COUNT(DISTINCT author) FROM books WHERE `+where, args...).
+		Scan(&stats.TotalBooks, &stats.TotalAuthors)
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.BooksPerAuthor, err = queryCounts(ctx, `
+		SELECT author, COUNT(*) FROM books WHERE `+where+`
+		GROUP BY author ORDER BY COUNT(*) DESC, author`, args...); err != nil {
+		return stats, err
+	}
+
+	if stats.TopAuthors, err = queryCounts(ctx, `
+		SELECT author, COUNT(*) FROM books WHERE `+where+`
+		GROUP BY author ORDER BY COUNT(*) DESC, author LIMIT `+next,
+		append(append([]interface{}{}, args...), filter.TopAuthors)...); err != nil {
+		return stats, err
+	}
+
+	if stats.BooksPerDecade, err = queryCounts(ctx, `
+		SELECT CAST((year / 10) * 10 AS TEXT) || 's', COUNT(*) FROM books WHERE year IS NOT NULL AND (`+where+`)
+		GROUP BY year / 10 ORDER BY year / 10`, args...); err != nil {
+		return stats, err
+	}
+
+	month := monthExpr("created_at")
+	if stats.AddedPerMonth, err = queryCounts(ctx, `
+		SELECT `+month+`, COUNT(*) FROM books
+		WHERE created_at IS NOT NULL AND (`+where+`)
+		GROUP BY `+month+` ORDER BY `+month, args...); err != nil {
+		return stats, err
+	}
+
+	rows, err := db().QueryContext(ctx, `
+		SELECT (year / `+next+`) * `+next+` AS bucket, COUNT(*) FROM books
+		WHERE year IS NOT NULL AND (`+where+`)
+		GROUP BY bucket ORDER BY bucket`,
+		append(append([]interface{}{}, args...), filter.BucketSize)...)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+
+	stats.YearHistogram = []YearBucket{}
+	for rows.Next() {
+		var bucket YearBucket
+		if err := rows.Scan(&bucket.From, &bucket.Count); err != nil {
+			return stats, err
+		}
+		bucket.To = bucket.From + filter.BucketSize - 1
+		stats.YearHistogram = append(stats.YearHistogram, bucket)
+	}
+	return stats, rows.Err()
+}
+
+// monthExpr mengembalikan ekspresi SQL "YYYY-MM" untuk kolom timestamp sesuai driver database
+func monthExpr(column string) string {
+	if config.App.Database.Driver == config.DriverSQLite {
+		return "strftime('%Y-%m', " + column + ")"
+	}
+	return "TO_CHAR(DATE_TRUNC('month', " + column + "), 'YYYY-MM')"
+}
+
+func queryCounts(ctx context.Context, query string, args ...interface{}) ([]CountByLabel, error) {
+	rows, err := db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []CountByLabel{}
+	for rows.Next() {
+		var c CountByLabel
+		if err := rows.Scan(&c.Label, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// Perubahan data selama agregasi berjalan tidak boleh tertimpa hasil agregasi yang basi
+func TestStatsCacheSkipsFillAfterBooksChanged(t *testing.T) {
+	const key = "test-stats-cache"
+	t.Cleanup(catalogStatsCache.purge)
+
+	if _, _, ok := catalogStatsCache.get(key); ok {
+		t.Fatal("cache seharusnya masih kosong")
+	}
+	_, generation, _ := catalogStatsCache.get(key)
+
+	// Penulisan terjadi di tengah query agregasi
+	notifyBooksChanged()
+
+	catalogStatsCache.put(key, generation, CatalogStats{TotalBooks: 21}, time.Minute)
+	if cached, _, ok := catalogStatsCache.get(key); ok {
+		t.Fatalf("statistik basi tersimpan setelah data berubah: %+v", cached)
+	}
+
+	_, generation, _ = catalogStatsCache.get(key)
+	catalogStatsCache.put(key, generation, CatalogStats{TotalBooks: 22}, time.Minute)
+	cached, _, ok := catalogStatsCache.get(key)
+	if !ok || cached.TotalBooks != 22 {
+		t.Fatalf("statistik tanpa perubahan data seharusnya tersimpan, dapat %+v (ok=%v)", cached, ok)
+	}
+}
@@ -0,0 +1,284 @@
+package models
+
+import (
+	"context"
+	"crud-buku-go/cache"
+	"crud-buku-go/config"
+	"crud-buku-go/lifecycle"
+	"crud-buku-go/metrics"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BookStore adalah penyimpanan data buku
+type BookStore interface {
+	GetAllBooks(ctx context.Context) ([]Book, error)
+	GetBookByID(ctx context.Context, id int) (Book, error)
+	CreateBook(ctx context.Context, book *Book) error
+	UpdateBook(ctx context.Context, id int, book *Book) error
+	DeleteBook(ctx context.Context, id int) error
+}
+
+// Store adalah BookStore yang dipakai oleh fungsi-fungsi data buku di paket ini
+var Store BookStore = PostgresStore{}
+
+// ConfigureStore memilih implementasi Store sesuai DB_DRIVER, lalu memasang cache
+// baca di depannya berdasarkan konfigurasi cache (BOOK_CACHE_SIZE bernilai 0 mematikan cache).
+func ConfigureStore() {
+	Store = NewStore(config.App.Database.Driver)
+
+	size := config.App.Cache.BookSize
+	if size == 0 {
+		slog.Info("Cache buku dimatikan (BOOK_CACHE_SIZE=0)")
+		return
+	}
+
+	itemTTL := config.App.Cache.BookTTL
+	listTTL := config.App.Cache.BookListTTL
+	Store = NewCachedStore(Store, cache.NewLRU(size, itemTTL), listTTL)
+	slog.Info("Cache buku aktif", "size", size, "item_ttl", itemTTL, "list_ttl", listTTL)
+}
+
+// NewStore mengembalikan BookStore untuk driver database
+func NewStore(driver string) BookStore {
+	if driver == config.DriverSQLite {
+		return SQLiteStore{}
+	}
+	return PostgresStore{}
+}
+
+// CacheStats mengembalikan statistik cache buku, atau false jika cache tidak aktif
+func CacheStats() (cache.Stats, bool) {
+	cached, ok := Store.(*CachedStore)
+	if !ok {
+		return cache.Stats{}, false
+	}
+	return cached.cache.Stats(), true
+}
+
+// GetAllBooks mengambil semua buku
+func GetAllBooks(ctx context.Context) ([]Book, error) {
+	books, err := Store.GetAllBooks(ctx)
+	err = Classify(err)
+	logStoreError(ctx, "get_all", 0, err)
+	return books, err
+}
+
+// GetBookByID mengambil satu buku berdasarkan ID
+func GetBookByID(ctx context.Context, id int) (Book, error) {
+	book, err := Store.GetBookByID(ctx, id)
+	err = Classify(err)
+	logStoreError(ctx, "get", id, err)
+	return book, err
+}
+
+// CreateBook menambahkan buku baru
+func CreateBook(ctx context.Context, book *Book) error {
+	// Menggunakan goroutine untuk logging (contoh sederhana)
+	// Dalam aplikasi nyata, ini bisa untuk tugas background yang lebih kompleks
+	// lifecycle.Go memastikan goroutine ini selesai sebelum pool database ditutup saat shutdown
+	// Context tanpa cancel tetap membawa request ID walaupun request sudah selesai
+	title, bgCtx := book.Title, context.WithoutCancel(ctx)
+	lifecycle.Go(func() {
+		slog.DebugContext(bgCtx, "Goroutine: memulai proses pembuatan buku", "title", title)
+		// Simulasi pekerjaan tambahan
+		time.Sleep(100 * time.Millisecond)
+		slog.DebugContext(bgCtx, "Goroutine: selesai proses pembuatan buku", "title", title)
+	})
+
+	if err := Classify(Store.CreateBook(ctx, book)); err != nil {
+		logStoreError(ctx, "create", 0, err)
+		return err
+	}
+	metrics.BooksCreated.Inc()
+	notifyBooksChanged()
+	return nil
+}
+
+// UpdateBook memperbarui data buku
+func UpdateBook(ctx context.Context, id int, book *Book) error {
+	// Menggunakan goroutine untuk logging pembaruan
+	title, bgCtx := book.Title, context.WithoutCancel(ctx)
+	lifecycle.Go(func() {
+		slog.DebugContext(bgCtx, "Goroutine: memulai proses pembaruan buku", "id", id, "title", title)
+		time.Sleep(50 * time.Millisecond)
+		slog.DebugContext(bgCtx, "Goroutine: selesai proses pembaruan buku", "id", id)
+	})
+
+	if err := Classify(Store.UpdateBook(ctx, id, book)); err != nil {
+		logStoreError(ctx, "update", id, err)
+		return err
+	}
+	metrics.BooksUpdated.Inc()
+	notifyBooksChanged()
+	return nil
+}
+
+// DeleteBook menghapus buku
+func DeleteBook(ctx context.Context, id int) error {
+	// Menggunakan goroutine untuk logging penghapusan
+	bgCtx := context.WithoutCancel(ctx)
+	lifecycle.Go(func() {
+		slog.DebugContext(bgCtx, "Goroutine: memulai proses penghapusan buku", "id", id)
+		time.Sleep(50 * time.Millisecond)
+		slog.DebugContext(bgCtx, "Goroutine: selesai proses penghapusan buku", "id", id)
+	})
+
+	if err := Classify(Store.DeleteBook(ctx, id)); err != nil {
+		logStoreError(ctx, "delete", id, err)
+		return err
+	}
+	metrics.BooksDeleted.Inc()
+	notifyBooksChanged()
+	return nil
+}
+
+// logStoreError mencatat error dari store beserta request ID di ctx.
+// Buku yang tidak ditemukan atau bentrok dicatat sebagai warning karena biasanya kesalahan klien.
+func logStoreError(ctx context.Context, op string, id int, err error) {
+	if err == nil {
+		return
+	}
+	level := slog.LevelError
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		level = slog.LevelWarn
+	}
+	attrs := []any{"op", op, "error", err}
+	if id != 0 {
+		attrs = append(attrs, "book_id", id)
+	}
+	slog.Log(ctx, level, "operasi store buku gagal", attrs...)
+}
+
+const allBooksCacheKey = "books:all"
+
+func bookCacheKey(id int) string {
+	return "book:" + strconv.Itoa(id)
+}
+
+// CachedStore adalah BookStore read-through yang menyimpan hasil baca di cache
+// dan menghapus entri terkait setiap kali ada penulisan (write-through invalidation).
+type CachedStore struct {
+	next    BookStore
+	cache   cache.Cache
+	listTTL time.Duration
+	// generation naik setiap invalidasi agar hasil baca yang dimulai sebelum
+	// penulisan tidak disimpan kembali ke cache sebagai data basi. mu menjaga
+	// generation sehingga pemeriksaan generasi dan Set tidak bisa diselingi invalidasi.
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewCachedStore membungkus next dengan cache; listTTL dipakai untuk daftar semua buku
+func NewCachedStore(next BookStore, c cache.Cache, listTTL time.Duration) *CachedStore {
+	return &CachedStore{next: next, cache: c, listTTL: listTTL}
+}
+
+// GetAllBooks membaca daftar buku dari cache atau dari store di belakangnya
+func (s *CachedStore) GetAllBooks(ctx context.Context) ([]Book, error) {
+	if cached, ok := s.cache.Get(allBooksCacheKey); ok {
+		return append([]Book(nil), cached.([]Book)...), nil
+	}
+
+	generation := s.currentGeneration()
+	books, err := s.next.GetAllBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.store(generation, allBooksCacheKey, append([]Book(nil), books...), s.listTTL)
+	return books, nil
+}
+
+// GetBookByID membaca satu buku dari cache atau dari store di belakangnya
+func (s *CachedStore) GetBookByID(ctx context.Context, id int) (Book, error) {
+	key := bookCacheKey(id)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(Book), nil
+	}
+
+	generation := s.currentGeneration()
+	book, err := s.next.GetBookByID(ctx, id)
+	if err != nil {
+		return book, err
+	}
+	s.store(generation, key, book, 0)
+	return book, nil
+}
+
+// CreateBook menulis ke store lalu membuang cache daftar buku
+func (s *CachedStore) CreateBook(ctx context.Context, book *Book) error {
+	err := s.next.CreateBook(ctx, book)
+	s.invalidate(allBooksCacheKey)
+	return err
+}
+
+// UpdateBook menulis ke store lalu membuang cache buku tersebut dan daftar buku
+func (s *CachedStore) UpdateBook(ctx context.Context, id int, book *Book) error {
+	err := s.next.UpdateBook(ctx, id, book)
+	s.invalidate(bookCacheKey(id), allBooksCacheKey)
+	return err
+}
+
+// DeleteBook menghapus dari store lalu membuang cache buku tersebut dan daftar buku
+func (s *CachedStore) DeleteBook(ctx context.Context, id int) error {
+	err := s.next.DeleteBook(ctx, id)
+	s.invalidate(bookCacheKey(id), allBooksCacheKey)
+	return err
+}
+
+// InvalidateBook membuang cache satu buku dan daftar buku, misalnya setelah perubahan dari luar proses
+func (s *CachedStore) InvalidateBook(id int) {
+	s.invalidate(bookCacheKey(id), allBooksCacheKey)
+}
+
+// Purge membuang seluruh isi cache
+func (s *CachedStore) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	s.cache.Purge()
+}
+
+func (s *CachedStore) invalidate(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	for _, key := range keys {
+		s.cache.Delete(key)
+	}
+}
+
+func (s *CachedStore) currentGeneration() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generation
+}
+
+// store menyimpan hasil baca ke cache hanya jika belum ada invalidasi sejak generation
+func (s *CachedStore) store(generation uint64, key string, value interface{}, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation == generation {
+		s.cache.Set(key, value, ttl)
+	}
+}
+
+// ApplyExternalChange membuang cache buku id dan menjalankan hook perubahan,
+// dipakai ketika perubahan berasal dari proses lain (misalnya replika lain via NOTIFY)
+func ApplyExternalChange(id int) {
+	if cached, ok := Store.(*CachedStore); ok {
+		cached.InvalidateBook(id)
+	}
+	notifyBooksChanged()
+}
+
+// PurgeCaches membuang seluruh cache buku lokal dan menjalankan hook perubahan
+func PurgeCaches() {
+	if cached, ok := Store.(*CachedStore); ok {
+		cached.Purge()
+	}
+	notifyBooksChanged()
+}
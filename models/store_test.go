@@ -0,0 +1,107 @@
+package models_test
+
+import (
+	"context"
+	"crud-buku-go/cache"
+	"crud-buku-go/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore adalah BookStore di memori untuk menguji CachedStore tanpa database
+type memoryStore struct {
+	mu    sync.Mutex
+	books map[int]models.Book
+}
+
+func (m *memoryStore) GetAllBooks(ctx context.Context) ([]models.Book, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var books []models.Book
+	for _, book := range m.books {
+		books = append(books, book)
+	}
+	return books, nil
+}
+
+func (m *memoryStore) GetBookByID(ctx context.Context, id int) (models.Book, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book, ok := m.books[id]
+	if !ok {
+		return book, &models.Error{Kind: models.ErrNotFound, Message: "buku tidak ditemukan"}
+	}
+	return book, nil
+}
+
+func (m *memoryStore) CreateBook(ctx context.Context, book *models.Book) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book.ID = len(m.books) + 1
+	m.books[book.ID] = *book
+	return nil
+}
+
+func (m *memoryStore) UpdateBook(ctx context.Context, id int, book *models.Book) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book.ID = id
+	m.books[id] = *book
+	return nil
+}
+
+func (m *memoryStore) DeleteBook(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.books, id)
+	return nil
+}
+
+// hookedCache menjalankan beforeSet sekali, tepat sebelum Set pertama diteruskan ke LRU
+type hookedCache struct {
+	*cache.LRU
+	once      sync.Once
+	beforeSet func()
+}
+
+func (c *hookedCache) Set(key string, value interface{}, ttl time.Duration) {
+	c.once.Do(c.beforeSet)
+	c.LRU.Set(key, value, ttl)
+}
+
+// Penulisan yang terjadi di antara pemeriksaan generasi dan Set pada pembacaan tidak boleh
+// membuat hasil baca lama tersimpan kembali ke cache
+func TestCachedStoreWriteDuringReadFill(t *testing.T) {
+	ctx := context.Background()
+	next := &memoryStore{books: map[int]models.Book{1: {ID: 1, Title: "Lama", Author: "A", Year: 2000}}}
+	hooked := &hookedCache{LRU: cache.NewLRU(10, time.Minute)}
+	store := models.NewCachedStore(next, hooked, time.Minute)
+
+	written := make(chan struct{})
+	hooked.beforeSet = func() {
+		go func() {
+			defer close(written)
+			store.UpdateBook(ctx, 1, &models.Book{Title: "Baru", Author: "A", Year: 2000})
+		}()
+		// Beri kesempatan penulisan selesai lebih dulu; dengan penguncian yang benar
+		// invalidasi menunggu sampai Set ini selesai
+		select {
+		case <-written:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if _, err := store.GetBookByID(ctx, 1); err != nil {
+		t.Fatalf("GetBookByID: %v", err)
+	}
+	<-written
+
+	got, err := store.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID setelah UpdateBook: %v", err)
+	}
+	if got.Title != "Baru" {
+		t.Errorf("judul = %q setelah UpdateBook, seharusnya %q (data basi tersimpan di cache)", got.Title, "Baru")
+	}
+}
@@ -0,0 +1,267 @@
+// Package storetest menyediakan rangkaian uji perilaku untuk implementasi models.BookStore.
+// Seperti testing/fstest, Run tidak bergantung pada paket testing dan mengembalikan
+// semua pelanggaran sekaligus, sehingga bisa dipakai dari test maupun dari program biasa.
+// Setiap implementasi (PostgresStore, SQLiteStore, CachedStore) beserta pencarian full-text
+// database-nya harus lolos rangkaian yang sama.
+package storetest
+
+import (
+	"context"
+	"crud-buku-go/models"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// missingID adalah ID yang dianggap tidak pernah ada; masih muat di kolom INT PostgreSQL
+const missingID = math.MaxInt32
+
+// timestampTolerance adalah selisih waktu yang diterima antara nilai yang dikembalikan
+// saat menulis dan nilai yang dibaca kembali (PostgreSQL menyimpan hingga mikrodetik)
+const timestampTolerance = time.Second
+
+// SearchFunc menjalankan pencarian full-text pada database yang sama dengan store,
+// misalnya models.FullTextSearchBooks untuk PostgresStore atau models.FTS5SearchBooks
+// untuk SQLiteStore
+type SearchFunc func(ctx context.Context, query string) ([]models.SearchResult, error)
+
+// Run menjalankan rangkaian uji perilaku terhadap store dan pencarian full-text-nya.
+// Store boleh sudah berisi data; buku yang dibuat selama uji dihapus kembali jika memungkinkan.
+func Run(ctx context.Context, store models.BookStore, search SearchFunc) error {
+	r := &runner{ctx: ctx, store: store, search: search}
+	r.createAndGet()
+	r.listOrder()
+	r.update()
+	r.remove()
+	r.missing()
+	r.searchMatches()
+	r.searchFollowsWrites()
+	r.cleanup()
+	return errors.Join(r.problems...)
+}
+
+type runner struct {
+	ctx      context.Context
+	store    models.BookStore
+	search   SearchFunc
+	problems []error
+	created  []int
+}
+
+func (r *runner) errorf(format string, args ...interface{}) {
+	r.problems = append(r.problems, fmt.Errorf(format, args...))
+}
+
+func (r *runner) create(title, author string, year int) (models.Book, bool) {
+	book := models.Book{Title: title, Author: author, Year: year}
+	if err := r.store.CreateBook(r.ctx, &book); err != nil {
+		r.errorf("CreateBook(%q): %v", title, err)
+		return book, false
+	}
+	r.created = append(r.created, book.ID)
+	return book, true
+}
+
+func (r *runner) createAndGet() {
+	before := time.Now().Add(-timestampTolerance)
+	book, ok := r.create("storetest: Laskar Pelangi", "Andrea Hirata", 2005)
+	if !ok {
+		return
+	}
+	if book.ID <= 0 {
+		r.errorf("CreateBook: ID = %d, harus positif", book.ID)
+	}
+	if book.CreatedAt.Before(before) || book.UpdatedAt.Before(before) {
+		r.errorf("CreateBook: created_at/updated_at tidak diisi (%v, %v)", book.CreatedAt, book.UpdatedAt)
+	}
+
+	got, err := r.store.GetBookByID(r.ctx, book.ID)
+	if err != nil {
+		r.errorf("GetBookByID(%d) setelah CreateBook: %v", book.ID, err)
+		return
+	}
+	r.sameBook("GetBookByID setelah CreateBook", got, book)
+}
+
+func (r *runner) listOrder() {
+	first, ok1 := r.create("storetest: Bumi Manusia", "Pramoedya Ananta Toer", 1980)
+	second, ok2 := r.create("storetest: Anak Semua Bangsa", "Pramoedya Ananta Toer", 1981)
+	if !ok1 || !ok2 {
+		return
+	}
+	if second.ID <= first.ID {
+		r.errorf("CreateBook: ID buku berikutnya (%d) harus lebih besar dari %d", second.ID, first.ID)
+	}
+
+	books, err := r.store.GetAllBooks(r.ctx)
+	if err != nil {
+		r.errorf("GetAllBooks: %v", err)
+		return
+	}
+	found := 0
+	for i, book := range books {
+		if i > 0 && books[i-1].ID >= book.ID {
+			r.errorf("GetAllBooks: tidak terurut berdasarkan ID (%d sebelum %d)", books[i-1].ID, book.ID)
+		}
+		switch book.ID {
+		case first.ID:
+			r.sameBook("GetAllBooks", book, first)
+			found++
+		case second.ID:
+			r.sameBook("GetAllBooks", book, second)
+			found++
+		}
+	}
+	if found != 2 {
+		r.errorf("GetAllBooks: hanya %d dari 2 buku baru yang ditemukan", found)
+	}
+}
+
+func (r *runner) update() {
+	book, ok := r.create("storetest: Ronggeng", "Ahmad Tohari", 1980)
+	if !ok {
+		return
+	}
+
+	changed := models.Book{Title: "storetest: Ronggeng Dukuh Paruk", Author: "Ahmad Tohari", Year: 1982}
+	if err := r.store.UpdateBook(r.ctx, book.ID, &changed); err != nil {
+		r.errorf("UpdateBook(%d): %v", book.ID, err)
+		return
+	}
+	if changed.ID != book.ID {
+		r.errorf("UpdateBook: ID = %d, seharusnya %d", changed.ID, book.ID)
+	}
+	if changed.UpdatedAt.Before(book.UpdatedAt) {
+		r.errorf("UpdateBook: updated_at mundur (%v sebelum %v)", changed.UpdatedAt, book.UpdatedAt)
+	}
+
+	got, err := r.store.GetBookByID(r.ctx, book.ID)
+	if err != nil {
+		r.errorf("GetBookByID(%d) setelah UpdateBook: %v", book.ID, err)
+		return
+	}
+	if got.Title != changed.Title || got.Author != changed.Author || got.Year != changed.Year {
+		r.errorf("GetBookByID setelah UpdateBook: dapat %+v, seharusnya %+v", got, changed)
+	}
+	if !closeTo(got.CreatedAt, book.CreatedAt) {
+		r.errorf("UpdateBook: created_at berubah dari %v menjadi %v", book.CreatedAt, got.CreatedAt)
+	}
+}
+
+func (r *runner) remove() {
+	book, ok := r.create("storetest: Saman", "Ayu Utami", 1998)
+	if !ok {
+		return
+	}
+	if err := r.store.DeleteBook(r.ctx, book.ID); err != nil {
+		r.errorf("DeleteBook(%d): %v", book.ID, err)
+		return
+	}
+	if _, err := r.store.GetBookByID(r.ctx, book.ID); err == nil {
+		r.errorf("GetBookByID(%d) setelah DeleteBook: seharusnya gagal", book.ID)
+	}
+	r.expectNotFound("DeleteBook dua kali", r.store.DeleteBook(r.ctx, book.ID))
+}
+
+func (r *runner) missing() {
+	_, err := r.store.GetBookByID(r.ctx, missingID)
+	r.expectNotFound("GetBookByID untuk ID yang tidak ada", err)
+
+	book := models.Book{Title: "storetest: tidak ada", Author: "-", Year: 2000}
+	r.expectNotFound("UpdateBook untuk ID yang tidak ada", r.store.UpdateBook(r.ctx, missingID, &book))
+	r.expectNotFound("DeleteBook untuk ID yang tidak ada", r.store.DeleteBook(r.ctx, missingID))
+}
+
+// Kata-kata di buku uji pencarian sengaja tidak umum agar tidak bentrok dengan data yang
+// sudah ada, dan tidak berubah oleh stemming bahasa Inggris di PostgreSQL
+const (
+	searchWord   = "zorblat"
+	searchPrefix = "zorbl"
+)
+
+func (r *runner) searchMatches() {
+	inTitle, ok1 := r.create("storetest: Zorblat Pelangi", "Wendo Kruft", 2005)
+	inAuthor, ok2 := r.create("storetest: Laskar Kruft", "Zorblat Hirata", 2005)
+	if !ok1 || !ok2 {
+		return
+	}
+
+	// Cocok di judul lebih relevan daripada cocok di penulis
+	r.expectSearch("kata", searchWord, inTitle.ID, inAuthor.ID)
+	r.expectSearch("awalan kata", searchPrefix, inTitle.ID, inAuthor.ID)
+	r.expectSearch("huruf besar", "ZORBLAT", inTitle.ID, inAuthor.ID)
+	r.expectSearch("semua kata", searchWord+" pelangi", inTitle.ID)
+	r.expectSearch("kata yang tidak ada", searchWord+" qwxzv")
+}
+
+func (r *runner) searchFollowsWrites() {
+	updated, ok1 := r.create("storetest: Zorblat Kronik", "Wendo Morvik", 1999)
+	deleted, ok2 := r.create("storetest: Kronik Zorblat Baru", "Wendo Morvik", 1999)
+	if !ok1 || !ok2 {
+		return
+	}
+
+	changed := models.Book{Title: "storetest: Trevalin", Author: updated.Author, Year: updated.Year}
+	if err := r.store.UpdateBook(r.ctx, updated.ID, &changed); err != nil {
+		r.errorf("UpdateBook(%d): %v", updated.ID, err)
+		return
+	}
+	if err := r.store.DeleteBook(r.ctx, deleted.ID); err != nil {
+		r.errorf("DeleteBook(%d): %v", deleted.ID, err)
+		return
+	}
+
+	r.expectSearch("judul baru setelah UpdateBook", "trevalin", updated.ID)
+	r.expectSearch("judul lama setelah UpdateBook dan DeleteBook", "kronik morvik")
+}
+
+// expectSearch menjalankan pencarian query dan membandingkan urutan hasil yang termasuk
+// buku buatan uji ini dengan want
+func (r *runner) expectSearch(what, query string, want ...int) {
+	results, err := r.search(r.ctx, query)
+	if err != nil {
+		r.errorf("pencarian %s (%q): %v", what, query, err)
+		return
+	}
+	own := make(map[int]bool, len(r.created))
+	for _, id := range r.created {
+		own[id] = true
+	}
+	got := []int{}
+	for _, result := range results {
+		if own[result.ID] {
+			got = append(got, result.ID)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		r.errorf("pencarian %s (%q): dapat ID %v, seharusnya %v", what, query, got, want)
+	}
+}
+
+func (r *runner) cleanup() {
+	for _, id := range r.created {
+		// Buku yang sudah dihapus oleh uji remove akan gagal di sini, dan itu wajar
+		_ = r.store.DeleteBook(r.ctx, id)
+	}
+}
+
+func (r *runner) expectNotFound(what string, err error) {
+	if !errors.Is(err, models.ErrNotFound) {
+		r.errorf("%s: error = %v, seharusnya %v", what, err, models.ErrNotFound)
+	}
+}
+
+func (r *runner) sameBook(what string, got, want models.Book) {
+	if got.ID != want.ID || got.Title != want.Title || got.Author != want.Author || got.Year != want.Year {
+		r.errorf("%s: dapat %+v, seharusnya %+v", what, got, want)
+	}
+	if !closeTo(got.CreatedAt, want.CreatedAt) || !closeTo(got.UpdatedAt, want.UpdatedAt) {
+		r.errorf("%s: timestamp %v/%v tidak cocok dengan %v/%v", what, got.CreatedAt, got.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+	}
+}
+
+func closeTo(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d > -timestampTolerance && d < timestampTolerance
+}
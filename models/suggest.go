@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"crud-buku-go/searchql"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxSuggestCacheEntries membatasi jumlah prefix yang disimpan di cache
+const maxSuggestCacheEntries = 1024
+
+// Suggestion adalah satu kandidat autocomplete untuk judul atau penulis
+type Suggestion struct {
+	Text  string `json:"text"`
+	Field string `json:"field"`
+	Books int    `json:"books"`
+}
+
+// @Description Hasil autocomplete judul dan penulis
+// SuggestResponse adalah payload response untuk endpoint autocomplete
+type SuggestResponse struct {
+	Prefix      string       `json:"prefix"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// suggestCache menyimpan hasil autocomplete per prefix di memori proses.
+// Seluruh isi cache dibuang setiap kali data buku berubah.
+type suggestCache struct {
+	mu         sync.RWMutex
+	entries    map[string][]Suggestion
+	generation uint64
+}
+
+var suggestions = &suggestCache{entries: make(map[string][]Suggestion)}
+
+func init() {
+	OnBooksChanged(InvalidateSuggestions)
+}
+
+// get juga mengembalikan generasi cache saat ini agar put bisa menolak hasil
+// query yang dimulai sebelum invalidasi terjadi.
+func (c *suggestCache) get(key string) ([]Suggestion, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result, ok := c.entries[key]
+	return result, c.generation, ok
+}
+
+func (c *suggestCache) put(key string, generation uint64, result []Suggestion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if len(c.entries) >= maxSuggestCacheEntries {
+		c.entries = make(map[string][]Suggestion)
+	}
+	c.entries[key] = result
+}
+
+func (c *suggestCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string][]Suggestion)
+	c.generation++
+}
+
+// InvalidateSuggestions mengosongkan cache autocomplete, dipanggil setelah data buku berubah
+func InvalidateSuggestions() {
+	suggestions.reset()
+}
+
+// SuggestBooks mengembalikan kandidat judul dan penulis yang diawali prefix.
+// Prefix yang kosong setelah spasi dibuang tidak menghasilkan kandidat.
+// Hasil diurutkan berdasarkan jumlah buku, lalu teks terpendek, dan disimpan di cache per prefix.
+// Query memakai pola LIKE 'prefix%' agar indeks LOWER(...) text_pattern_ops dapat digunakan.
+func SuggestBooks(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	normalized := strings.ToLower(strings.TrimSpace(prefix))
+	// Prefix kosong cocok dengan semua buku, jadi tidak ada kandidat yang berarti
+	if normalized == "" {
+		return []Suggestion{}, nil
+	}
+	key := normalized + "\x00" + strconv.Itoa(limit)
+	cached, generation, ok := suggestions.get(key)
+	if ok {
+		return cached, nil
+	}
+
+	rows, err := db().QueryContext(ctx, `
+		SELECT text, field, books
+		FROM (
+			SELECT title AS text, 'title' AS field, COUNT(*) AS books
+			FROM books WHERE LOWER(title) LIKE $1 ESCAPE '\' GROUP BY title
+			UNION ALL
+			SELECT author, 'author', COUNT(*)
+			FROM books WHERE LOWER(author) LIKE $1 ESCAPE '\' GROUP BY author
+		) AS candidates
+		ORDER BY books DESC, LENGTH(text), text
+		LIMIT $2
+	`, searchql.EscapeLike(normalized)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []Suggestion{}
+	for rows.Next() {
+		var s Suggestion
+		if err := rows.Scan(&s.Text, &s.Field, &s.Books); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	suggestions.put(key, generation, result)
+	return result, nil
+}
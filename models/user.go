@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// User adalah akun yang bisa login. Role menentukan izinnya (reader, librarian, admin);
+// password hanya disimpan dalam bentuk hash bcrypt. ExternalID terisi untuk akun yang
+// dibuat lewat login OIDC.
+type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	ExternalID   string    `json:"external_id,omitempty"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Error pengguna
+var (
+	ErrUserNotFound  = &Error{Kind: ErrNotFound, Message: "pengguna tidak ditemukan"}
+	ErrUsernameTaken = &Error{Kind: ErrConflict, Message: "username sudah dipakai akun lain"}
+)
+
+const userColumns = "id, username, role, external_id, password_hash, created_at, updated_at"
+
+// Query pengguna memakai placeholder $n dan RETURNING yang didukung PostgreSQL maupun SQLite
+
+// GetUserByUsername mengambil pengguna berdasarkan username
+func GetUserByUsername(ctx context.Context, username string) (User, error) {
+	return scanUser(db().QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE username = $1", username))
+}
+
+// GetUserByID mengambil pengguna berdasarkan ID
+func GetUserByID(ctx context.Context, id int) (User, error) {
+	return scanUser(db().QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE id = $1", id))
+}
+
+// GetUserByExternalID mengambil pengguna OIDC berdasarkan "<issuer>|<sub>"
+func GetUserByExternalID(ctx context.Context, externalID string) (User, error) {
+	return scanUser(db().QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE external_id = $1", externalID))
+}
+
+// ListUsers mengambil semua pengguna, urut berdasarkan ID
+func ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := db().QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// rowScanner adalah *sql.Row atau *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (User, error) {
+	var user User
+	var externalID sql.NullString
+	err := row.Scan(&user.ID, &user.Username, &user.Role, &externalID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, ErrUserNotFound
+	}
+	user.ExternalID = externalID.String
+	return user, err
+}
+
+// CreateUser menyimpan pengguna baru; PasswordHash harus sudah berisi hash
+func CreateUser(ctx context.Context, user *User) error {
+	var externalID sql.NullString
+	if user.ExternalID != "" {
+		externalID = sql.NullString{String: user.ExternalID, Valid: true}
+	}
+	now := time.Now()
+	return db().QueryRowContext(ctx, `INSERT INTO users (username, role, external_id, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`,
+		user.Username, user.Role, externalID, user.PasswordHash, now, now).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+}
+
+// SyncExternalUser mencari pengguna OIDC berdasarkan ExternalID dan memperbarui perannya
+// sesuai klaim terbaru, atau membuatnya jika belum ada. Username yang sudah dipakai akun
+// lain menghasilkan ErrUsernameTaken agar login OIDC tidak bisa mengambil alih akun lokal.
+func SyncExternalUser(ctx context.Context, user *User) error {
+	existing, err := GetUserByExternalID(ctx, user.ExternalID)
+	switch {
+	case err == nil:
+		if existing.Role != user.Role {
+			if err := UpdateUserRole(ctx, existing.ID, user.Role); err != nil {
+				return err
+			}
+			existing.Role = user.Role
+		}
+		*user = existing
+		return nil
+	case !errors.Is(err, ErrUserNotFound):
+		return err
+	}
+
+	if _, err := GetUserByUsername(ctx, user.Username); err == nil {
+		return ErrUsernameTaken
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+	return CreateUser(ctx, user)
+}
+
+// UpdateUserPassword mengganti hash password pengguna
+func UpdateUserPassword(ctx context.Context, id int, passwordHash string) error {
+	return updateUser(ctx, id, "password_hash", passwordHash)
+}
+
+// UpdateUserRole mengganti peran pengguna. Peran baru berlaku di token berikutnya,
+// paling lambat setelah token akses yang sedang dipakai kedaluwarsa.
+func UpdateUserRole(ctx context.Context, id int, role string) error {
+	return updateUser(ctx, id, "role", role)
+}
+
+// updateUser mengganti satu kolom pengguna; column harus berasal dari kode, bukan input
+func updateUser(ctx context.Context, id int, column string, value interface{}) error {
+	result, err := db().ExecContext(ctx, "UPDATE users SET "+column+" = $1, updated_at = $2 WHERE id = $3",
+		value, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
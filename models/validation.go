@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Batas data buku. Panjang judul dan penulis mengikuti kolom VARCHAR(255) dan dihitung
+// per karakter, bukan per byte, sama seperti PostgreSQL.
+const (
+	MaxTitleLength  = 255
+	MaxAuthorLength = 255
+	MinBookYear     = 1000
+)
+
+// Kode kesalahan validasi yang bisa dipakai klien tanpa membaca pesannya
+const (
+	CodeRequired     = "required"
+	CodeTooLong      = "too_long"
+	CodeOutOfRange   = "out_of_range"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+)
+
+// @Description Kesalahan validasi pada satu field
+// FieldError adalah kesalahan validasi pada satu field payload
+type FieldError struct {
+	Field   string `json:"field" example:"year"`
+	Code    string `json:"code" example:"out_of_range"`
+	Message string `json:"message" example:"Tahun harus antara 1000 dan 2026"`
+}
+
+// ValidationError berisi semua kesalahan validasi sebuah payload
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+	return "data tidak valid: " + strings.Join(messages, "; ")
+}
+
+// Unwrap membuat ValidationError cocok dengan ErrValidation
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// Add menambahkan kesalahan untuk field
+func (e *ValidationError) Add(field, code, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err mengembalikan e jika ada kesalahan, atau nil
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Normalize membuang spasi di awal dan akhir judul dan penulis
+func (b *Book) Normalize() {
+	b.Title = strings.TrimSpace(b.Title)
+	b.Author = strings.TrimSpace(b.Author)
+}
+
+// Validate memeriksa judul, penulis, dan tahun buku. Tahun tidak boleh melewati tahun
+// berjalan. Semua kesalahan dikembalikan sekaligus sebagai *ValidationError.
+func (b Book) Validate() error {
+	var v ValidationError
+	validateText(&v, "title", "Judul", b.Title, MaxTitleLength)
+	validateText(&v, "author", "Penulis", b.Author, MaxAuthorLength)
+
+	maxYear := time.Now().Year()
+	switch {
+	case b.Year == 0:
+		v.Add("year", CodeRequired, "Tahun tidak boleh kosong")
+	case b.Year < MinBookYear || b.Year > maxYear:
+		v.Add("year", CodeOutOfRange, fmt.Sprintf("Tahun harus antara %d dan %d", MinBookYear, maxYear))
+	}
+	return v.Err()
+}
+
+func validateText(v *ValidationError, field, label, value string, max int) {
+	switch {
+	case value == "":
+		v.Add(field, CodeRequired, label+" tidak boleh kosong")
+	case utf8.RuneCountInString(value) > max:
+		v.Add(field, CodeTooLong, fmt.Sprintf("%s maksimal %d karakter", label, max))
+	}
+}
@@ -0,0 +1,78 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseSize membatasi ukuran response dari identity provider
+const maxResponseSize = 1 << 20
+
+// Provider adalah bagian dokumen discovery OpenID Provider yang dipakai aplikasi
+type Provider struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint,omitempty"`
+	SigningAlgs           []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// Discover membaca dokumen <issuer>/.well-known/openid-configuration dan memastikan
+// issuer di dalamnya sama persis dengan issuer yang dikonfigurasi
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Provider, error) {
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	var provider Provider
+	if err := getJSON(ctx, client, wellKnown, &provider); err != nil {
+		return nil, fmt.Errorf("discovery OIDC gagal: %w", err)
+	}
+
+	if provider.Issuer != issuer {
+		return nil, fmt.Errorf("issuer di dokumen discovery (%q) tidak sama dengan OIDC_ISSUER (%q)", provider.Issuer, issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("dokumen discovery tidak memuat authorization_endpoint, token_endpoint, atau jwks_uri")
+	}
+	// Provider yang tidak mengiklankan metode PKCE tetap dicoba; yang mengiklankan harus mendukung S256
+	if len(provider.CodeChallengeMethods) > 0 && !contains(provider.CodeChallengeMethods, "S256") {
+		return nil, fmt.Errorf("provider tidak mendukung PKCE S256")
+	}
+	return &provider, nil
+}
+
+// getJSON menjalankan GET dan mendekode response JSON berstatus 200
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.Unmarshal(body, v)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
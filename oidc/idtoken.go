@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// clockSkew adalah toleransi perbedaan jam antara aplikasi dan provider
+const clockSkew = time.Minute
+
+// ErrInvalidIDToken dikembalikan jika ID token gagal diverifikasi
+var ErrInvalidIDToken = errors.New("ID token tidak valid")
+
+// IDToken adalah isi ID token yang sudah diverifikasi. Claims memuat seluruh claim mentah
+// agar claim username dan peran bisa dipilih lewat konfigurasi.
+type IDToken struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	Expiry   time.Time
+	IssuedAt time.Time
+	Nonce    string
+	Claims   map[string]interface{}
+}
+
+// audience menerima claim aud berbentuk string maupun array (OIDC Core 2)
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type idTokenClaims struct {
+	Issuer   string   `json:"iss"`
+	Subject  string   `json:"sub"`
+	Audience audience `json:"aud"`
+	AZP      string   `json:"azp"`
+	Expiry   int64    `json:"exp"`
+	IssuedAt int64    `json:"iat"`
+	Nonce    string   `json:"nonce"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verifier memeriksa ID token sesuai OIDC Core 3.1.3.7
+type verifier struct {
+	issuer   string
+	clientID string
+	keys     *keySet
+	now      func() time.Time
+}
+
+// verify memeriksa tanda tangan (RS256 atau ES256), iss, aud, azp, exp, iat, dan nonce
+func (v *verifier) verify(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: format JWT salah", ErrInvalidIDToken)
+	}
+
+	var h jwtHeader
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header tidak bisa dibaca", ErrInvalidIDToken)
+	}
+	if h.Alg != "RS256" && h.Alg != "ES256" {
+		return nil, fmt.Errorf("%w: algoritma %q tidak didukung", ErrInvalidIDToken, h.Alg)
+	}
+	key, err := v.keys.key(ctx, h.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: tanda tangan tidak bisa dibaca", ErrInvalidIDToken)
+	}
+	if err := verifySignature(h.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims tidak bisa dibaca", ErrInvalidIDToken)
+	}
+	var all map[string]interface{}
+	if err := decodeSegment(parts[1], &all); err != nil {
+		return nil, fmt.Errorf("%w: claims tidak bisa dibaca", ErrInvalidIDToken)
+	}
+
+	now := v.now()
+	switch {
+	case claims.Issuer != v.issuer:
+		return nil, fmt.Errorf("%w: issuer %q tidak dikenal", ErrInvalidIDToken, claims.Issuer)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: claim sub kosong", ErrInvalidIDToken)
+	case !contains(claims.Audience, v.clientID):
+		return nil, fmt.Errorf("%w: token bukan untuk client ini", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AZP != v.clientID:
+		return nil, fmt.Errorf("%w: azp tidak sama dengan client ID", ErrInvalidIDToken)
+	case claims.Expiry == 0 || !now.Before(time.Unix(claims.Expiry, 0).Add(clockSkew)):
+		return nil, fmt.Errorf("%w: token sudah kedaluwarsa", ErrInvalidIDToken)
+	case claims.IssuedAt != 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)):
+		return nil, fmt.Errorf("%w: iat berada di masa depan", ErrInvalidIDToken)
+	case nonce != "" && claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce tidak cocok", ErrInvalidIDToken)
+	}
+
+	return &IDToken{
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Audience: claims.Audience,
+		Expiry:   time.Unix(claims.Expiry, 0),
+		IssuedAt: time.Unix(claims.IssuedAt, 0),
+		Nonce:    claims.Nonce,
+		Claims:   all,
+	}, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("kunci bukan RSA")
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature)
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("kunci bukan EC")
+		}
+		// JWS memakai format R||S dengan panjang tetap, bukan ASN.1
+		if len(signature) != 64 {
+			return errors.New("panjang tanda tangan ES256 salah")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return errors.New("tanda tangan tidak cocok")
+		}
+		return nil
+	}
+	return fmt.Errorf("algoritma %q tidak didukung", alg)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
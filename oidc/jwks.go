@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+)
+
+// jwk adalah satu kunci publik di dokumen JWKS (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet menyimpan kunci publik provider dan mengambil ulang JWKS jika kid tidak dikenal,
+// sehingga rotasi kunci di provider tidak membutuhkan restart. Pengambilan ulang tidak perlu
+// dibatasi karena ID token hanya diterima langsung dari token endpoint, bukan dari browser.
+type keySet struct {
+	client *http.Client
+	uri    string
+
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+}
+
+func newKeySet(client *http.Client, uri string) *keySet {
+	return &keySet{client: client, uri: uri}
+}
+
+// key mengembalikan kunci publik untuk kid. kid kosong hanya diterima jika provider
+// memiliki tepat satu kunci.
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("kunci %q tidak ada di JWKS", kid)
+}
+
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *keySet) fetch(ctx context.Context) error {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.uri, &doc); err != nil {
+		return fmt.Errorf("gagal mengambil JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Kunci dengan tipe yang tidak didukung dilewati, bukan membatalkan seluruh JWKS
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	s.keys = keys
+	return nil
+}
+
+// publicKey mengubah JWK RSA atau EC P-256 menjadi kunci publik Go
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("eksponen RSA tidak valid")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("kurva %q tidak didukung", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("titik EC tidak berada di kurva")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("tipe kunci %q tidak didukung", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("bilangan base64url tidak valid")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
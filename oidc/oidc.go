@@ -0,0 +1,257 @@
+// Package oidc mengimplementasikan login OpenID Connect untuk staf: authorization code
+// flow dengan PKCE (S256), discovery provider, verifikasi ID token dengan JWKS, dan
+// pemetaan klaim (misalnya groups) ke peran aplikasi.
+package oidc
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/tracing"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRoleDenied dikembalikan jika klaim pengguna tidak cocok dengan peran mana pun
+// dan OIDC_DEFAULT_ROLE kosong
+var ErrRoleDenied = errors.New("akun ini tidak memiliki peran di aplikasi")
+
+// Identity adalah pengguna hasil login OIDC yang sudah dipetakan ke peran aplikasi
+type Identity struct {
+	// ExternalID adalah "<issuer>|<sub>", pengenal stabil pengguna di provider
+	ExternalID string
+	Username   string
+	Role       auth.Role
+}
+
+// Client menjalankan authorization code flow terhadap satu provider. Dokumen discovery
+// diambil saat pertama kali dibutuhkan dan diulang jika gagal, sehingga provider yang
+// belum siap saat startup tidak menghentikan aplikasi.
+type Client struct {
+	cfg         config.OIDCConfig
+	httpClient  *http.Client
+	roleMapping map[string]string
+
+	mu       sync.Mutex
+	provider *Provider
+	verifier *verifier
+}
+
+// NewClient membuat Client dari konfigurasi. httpClient nil berarti client default
+// dengan timeout 10 detik yang ikut dilacak oleh tracing.
+func NewClient(cfg config.OIDCConfig, httpClient *http.Client) (*Client, error) {
+	mapping, err := cfg.ParseRoleMapping()
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second, Transport: &tracing.Transport{}}
+	}
+	return &Client{cfg: cfg, httpClient: httpClient, roleMapping: mapping}, nil
+}
+
+var active *Client
+
+// Configure mengaktifkan login OIDC dengan konfigurasi cfg
+func Configure(cfg config.OIDCConfig) error {
+	client, err := NewClient(cfg, nil)
+	if err != nil {
+		return err
+	}
+	active = client
+	slog.Info("Login OIDC aktif", "issuer", cfg.Issuer, "client_id", cfg.ClientID,
+		"role_claim", cfg.RoleClaim, "default_role", cfg.DefaultRole)
+	return nil
+}
+
+// Active mengembalikan Client yang dikonfigurasi, atau nil jika login OIDC tidak aktif
+func Active() *Client {
+	return active
+}
+
+// discover mengembalikan dokumen discovery dan verifier ID token, mengambilnya jika belum ada
+func (c *Client) discover(ctx context.Context) (*Provider, *verifier, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.provider != nil {
+		return c.provider, c.verifier, nil
+	}
+
+	provider, err := Discover(ctx, c.httpClient, c.cfg.Issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.provider = provider
+	c.verifier = &verifier{
+		issuer:   provider.Issuer,
+		clientID: c.cfg.ClientID,
+		keys:     newKeySet(c.httpClient, provider.JWKSURI),
+		now:      time.Now,
+	}
+	return c.provider, c.verifier, nil
+}
+
+// AuthCodeURL mengembalikan URL authorization endpoint tempat browser diarahkan.
+// codeVerifier hanya disimpan di sisi aplikasi; provider menerima challenge S256-nya.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	provider, _, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization_endpoint tidak valid: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.cfg.ClientID)
+	q.Set("redirect_uri", c.cfg.RedirectURL)
+	q.Set("scope", strings.Join(strings.Fields(c.cfg.Scopes), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange menukar authorization code dengan token di token endpoint, memverifikasi
+// ID token-nya, lalu memetakan klaimnya ke Identity
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error) {
+	provider, v, err := c.discover(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// Client publik mengirim client_id di body; client rahasia memakai client_secret_basic
+	if c.cfg.ClientSecret == "" {
+		form.Set("client_id", c.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Identity{}, fmt.Errorf("token endpoint tidak bisa dihubungi: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return Identity{}, fmt.Errorf("response token endpoint tidak valid (status %d)", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Identity{}, fmt.Errorf("token endpoint menolak code: %s %s", token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return Identity{}, errors.New("response token endpoint tidak memuat id_token")
+	}
+
+	idToken, err := v.verify(ctx, token.IDToken, nonce)
+	if err != nil {
+		return Identity{}, err
+	}
+	return c.identity(idToken)
+}
+
+// identity mengambil username dan peran dari klaim ID token
+func (c *Client) identity(token *IDToken) (Identity, error) {
+	username, _ := token.Claims[c.cfg.UsernameClaim].(string)
+	username = strings.ToLower(strings.TrimSpace(username))
+	if username == "" {
+		return Identity{}, fmt.Errorf("ID token tidak memuat klaim %q", c.cfg.UsernameClaim)
+	}
+
+	role, err := c.mapRole(claimValues(token.Claims[c.cfg.RoleClaim]))
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{ExternalID: token.Issuer + "|" + token.Subject, Username: username, Role: role}, nil
+}
+
+// mapRole memilih peran tertinggi yang dipetakan dari nilai klaim, atau DefaultRole
+func (c *Client) mapRole(values []string) (auth.Role, error) {
+	best := -1
+	for _, value := range values {
+		name, ok := c.roleMapping[value]
+		if !ok {
+			continue
+		}
+		for i, role := range auth.Roles {
+			if string(role) == name && i > best {
+				best = i
+			}
+		}
+	}
+	if best >= 0 {
+		return auth.Roles[best], nil
+	}
+	if c.cfg.DefaultRole == "" {
+		return "", ErrRoleDenied
+	}
+	return auth.ParseRole(c.cfg.DefaultRole)
+}
+
+// claimValues mengubah klaim berbentuk string atau array menjadi daftar string
+func claimValues(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// RandomString membuat nilai acak base64url untuk state, nonce, dan code verifier PKCE
+func RandomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand gagal: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// CodeChallenge menghitung code_challenge S256 dari code verifier (RFC 7636 4.2)
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
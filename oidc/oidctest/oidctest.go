@@ -0,0 +1,236 @@
+// Package oidctest menyediakan OpenID Provider tiruan di atas httptest.Server untuk
+// mencoba login OIDC secara lokal tanpa identity provider sungguhan. Setiap request ke
+// /authorize langsung disetujui atas nama pengguna yang diatur lewat SetClaims.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Server adalah OpenID Provider tiruan. Issuer sama dengan URL server.
+type Server struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	mu     sync.Mutex
+	key    *rsa.PrivateKey
+	kid    string
+	claims map[string]interface{}
+	codes  map[string]authRequest
+}
+
+// authRequest adalah authorization code yang belum ditukar
+type authRequest struct {
+	challenge string
+	nonce     string
+	claims    map[string]interface{}
+}
+
+// NewServer menjalankan provider tiruan untuk satu client. clientSecret kosong berarti
+// client publik.
+func NewServer(clientID, clientSecret, redirectURL string) *Server {
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		claims:       map[string]interface{}{"sub": "user-1", "preferred_username": "staf"},
+		codes:        make(map[string]authRequest),
+	}
+	s.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetClaims mengatur klaim ID token untuk login berikutnya; sub wajib ada
+func (s *Server) SetClaims(claims map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims = claims
+}
+
+// RotateKey mengganti kunci penandatangan beserta kid-nya
+func (s *Server) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key, s.kid = key, randomHex(8)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	pub, kid := s.key.PublicKey, s.kid
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": kid,
+			"n":   b64(pub.N.Bytes()),
+			"e":   b64(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize langsung menyetujui request yang valid dan mengarahkan kembali dengan code
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Get("client_id") != s.ClientID:
+		http.Error(w, "client_id tidak dikenal", http.StatusBadRequest)
+		return
+	case q.Get("redirect_uri") != s.RedirectURL:
+		http.Error(w, "redirect_uri tidak terdaftar", http.StatusBadRequest)
+		return
+	case q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		http.Error(w, "request harus memakai code flow dengan PKCE S256", http.StatusBadRequest)
+		return
+	}
+
+	code := randomHex(16)
+	s.mu.Lock()
+	s.codes[code] = authRequest{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), claims: s.claims}
+	s.mu.Unlock()
+
+	redirect, _ := url.Parse(s.RedirectURL)
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token menukar code sekali pakai dengan ID token setelah memeriksa client dan PKCE
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	clientID, clientSecret, basic := r.BasicAuth()
+	if !basic {
+		clientID = r.PostForm.Get("client_id")
+	} else {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	s.mu.Lock()
+	req, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	key, kid := s.key, s.kid
+	s.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != s.RedirectURL {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if b64(sum[:]) != req.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   s.URL,
+		"aud":   s.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": req.nonce,
+	}
+	for k, v := range req.claims {
+		claims[k] = v
+	}
+	idToken, err := signRS256(key, kid, claims)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomHex(16),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func signRS256(key *rsa.PrivateKey, kid string, claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + b64(signature), nil
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval adalah jeda minimal antar pembersihan bucket yang sudah penuh
+const sweepInterval = time.Minute
+
+// MemoryStore menyimpan bucket di memori proses. Setiap instance memiliki kuotanya
+// sendiri, jadi kuota efektif dikalikan jumlah instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// fullAt adalah saat bucket penuh kembali; setelah itu bucket sama dengan bucket baru
+	fullAt time.Time
+}
+
+// NewMemoryStore membuat store kosong
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+// Name mengembalikan nama store
+func (*MemoryStore) Name() string {
+	return StoreMemory
+}
+
+// Take mengambil cost token dari bucket key
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, cost int) (Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, result := limit.spend(limit.refill(b.tokens, now.Sub(b.updated)), float64(cost))
+	b.tokens, b.updated, b.fullAt = tokens, now, now.Add(result.Reset)
+	return result, nil
+}
+
+// sweep membuang bucket yang sudah penuh agar memori tidak tumbuh terus oleh klien sekali lewat
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"crud-buku-go/lifecycle"
+	"database/sql"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// PostgresStore menyimpan bucket di tabel rate_limits sehingga kuota dibagi ke semua
+// instance. Waktu diambil dari jam database agar perbedaan jam antar instance tidak
+// memengaruhi pengisian token.
+type PostgresStore struct {
+	db        *sql.DB
+	lastSweep atomic.Int64
+}
+
+// NewPostgresStore membuat store di atas pool db; tabel rate_limits dibuat saat startup
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	s := &PostgresStore{db: db}
+	s.lastSweep.Store(time.Now().UnixNano())
+	return s
+}
+
+// Name mengembalikan nama store
+func (*PostgresStore) Name() string {
+	return StorePostgres
+}
+
+// Take mengambil cost token dari bucket key. Upsert pertama mengunci baris bucket
+// (atau membuatnya penuh) sampai transaksi selesai, sehingga request bersamaan dari
+// instance mana pun dengan key yang sama diproses bergantian.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, cost int) (Result, error) {
+	s.maybeSweep()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	var tokens, elapsed float64
+	err = tx.QueryRowContext(ctx, `INSERT INTO rate_limits (key, tokens, updated_at, full_at)
+		VALUES ($1, $2, now(), now())
+		ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+		RETURNING tokens, EXTRACT(EPOCH FROM now() - updated_at)`,
+		key, float64(limit.Burst)).Scan(&tokens, &elapsed)
+	if err != nil {
+		return Result{}, err
+	}
+
+	tokens, result := limit.spend(limit.refill(tokens, time.Duration(elapsed*float64(time.Second))), float64(cost))
+	_, err = tx.ExecContext(ctx, `UPDATE rate_limits
+		SET tokens = $2, updated_at = now(), full_at = now() + $3::double precision * INTERVAL '1 second'
+		WHERE key = $1`, key, tokens, result.Reset.Seconds())
+	if err != nil {
+		return Result{}, err
+	}
+	return result, tx.Commit()
+}
+
+// maybeSweep menghapus bucket yang sudah penuh di latar belakang, paling sering sekali
+// per sweepInterval per instance
+func (s *PostgresStore) maybeSweep() {
+	last := s.lastSweep.Load()
+	now := time.Now().UnixNano()
+	if time.Duration(now-last) < sweepInterval || !s.lastSweep.CompareAndSwap(last, now) {
+		return
+	}
+	lifecycle.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM rate_limits WHERE full_at < now()"); err != nil {
+			slog.Warn("Gagal membersihkan bucket rate limit", "error", err)
+		}
+	})
+}
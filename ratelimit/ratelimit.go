@@ -0,0 +1,168 @@
+// Package ratelimit membatasi jumlah request per klien dengan algoritma token bucket.
+// Setiap kelas rute (search, write, read) memiliki kuotanya sendiri, dan bucket disimpan
+// di Store yang bisa dipilih lewat konfigurasi: di memori proses atau dibagi ke semua
+// instance lewat PostgreSQL.
+package ratelimit
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Class adalah kelompok rute yang berbagi kuota
+type Class string
+
+// Kelas rute. Search dipisahkan dari read karena query full-text jauh lebih mahal.
+// Auth bukan kelas rute: bucket-nya per IP dan hanya berkurang saat autentikasi gagal.
+const (
+	ClassSearch Class = "search"
+	ClassWrite  Class = "write"
+	ClassRead   Class = "read"
+	ClassAuth   Class = "auth"
+)
+
+// Limit adalah kuota token bucket: Burst token yang terisi kembali merata selama Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Policy mengembalikan kuota dalam format header RateLimit-Policy, misalnya "30;w=60"
+func (l Limit) Policy() string {
+	return fmt.Sprintf("%d;w=%d", l.Burst, int(math.Ceil(l.Period.Seconds())))
+}
+
+// refill menambahkan token yang terkumpul selama elapsed, maksimal sebesar Burst
+func (l Limit) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * float64(l.Burst) / l.Period.Seconds()
+	}
+	return math.Min(tokens, float64(l.Burst))
+}
+
+// spend memakai cost token (0 atau 1) jika minimal satu token tersedia dan menghitung
+// sisa kuota
+func (l Limit) spend(tokens, cost float64) (float64, Result) {
+	result := Result{Limit: l.Burst}
+	if tokens >= 1 {
+		tokens -= cost
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.timeFor(1 - tokens)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = l.timeFor(float64(l.Burst) - tokens)
+	return tokens, result
+}
+
+// timeFor menghitung lama waktu sampai sejumlah token terisi kembali
+func (l Limit) timeFor(tokens float64) time.Duration {
+	return time.Duration(tokens / float64(l.Burst) * float64(l.Period))
+}
+
+// Result adalah hasil pengambilan satu token
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter adalah waktu sampai token berikutnya tersedia; 0 jika Allowed
+	RetryAfter time.Duration
+	// Reset adalah waktu sampai bucket penuh kembali
+	Reset time.Duration
+}
+
+// Store menyimpan bucket per key. Take harus atomik terhadap pemanggilan lain dengan key
+// yang sama, termasuk dari instance lain jika store dibagi.
+type Store interface {
+	// Name mengembalikan nama store untuk log
+	Name() string
+	// Take mengambil cost token dari bucket key; bucket baru dimulai dalam keadaan penuh.
+	// Cost 0 hanya memeriksa apakah masih ada token tanpa memakainya.
+	Take(ctx context.Context, key string, limit Limit, cost int) (Result, error)
+}
+
+// Nama store yang didukung
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+var stores = map[string]func() Store{
+	StoreMemory:   func() Store { return NewMemoryStore() },
+	StorePostgres: func() Store { return NewPostgresStore(config.DB) },
+}
+
+// NewStore membuat store berdasarkan nama
+func NewStore(name string) (Store, error) {
+	factory, ok := stores[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(stores))
+		for n := range stores {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("store rate limit %q tidak dikenal (pilihan: %s)", name, strings.Join(names, ", "))
+	}
+	return factory(), nil
+}
+
+var (
+	active Store
+	limits map[Class]Limit
+)
+
+// Configure memilih store dan kuota per kelas dari konfigurasi. Untuk store postgres,
+// Configure harus dipanggil setelah koneksi database dibuka.
+func Configure(cfg config.RateLimitConfig) error {
+	if !cfg.Enabled {
+		active, limits = nil, nil
+		slog.Info("Rate limit dinonaktifkan")
+		return nil
+	}
+
+	configured := make(map[Class]Limit)
+	for class, spec := range map[Class]string{ClassSearch: cfg.Search, ClassWrite: cfg.Write, ClassRead: cfg.Read, ClassAuth: cfg.Auth} {
+		burst, period, err := config.ParseRate(spec)
+		if err != nil {
+			return err
+		}
+		if burst > 0 {
+			configured[class] = Limit{Burst: burst, Period: period}
+		}
+	}
+	store, err := NewStore(cfg.Store)
+	if err != nil {
+		return err
+	}
+
+	active, limits = store, configured
+	slog.Info("Rate limit aktif", "store", store.Name(), "search", cfg.Search, "write", cfg.Write, "read", cfg.Read, "auth", cfg.Auth)
+	return nil
+}
+
+// LimitFor mengembalikan kuota kelas; false jika kelas tidak dibatasi
+func LimitFor(class Class) (Limit, bool) {
+	if active == nil {
+		return Limit{}, false
+	}
+	limit, ok := limits[class]
+	return limit, ok
+}
+
+// Take mengambil satu token untuk klien pada kelas rute. client adalah identitas stabil
+// pemanggil, misalnya "apikey:3", "user:7", atau "ip:203.0.113.9".
+func Take(ctx context.Context, class Class, client string, limit Limit) (Result, error) {
+	return active.Take(ctx, string(class)+":"+client, limit, 1)
+}
+
+// Check seperti Take, tetapi tidak memakai token; Allowed bernilai false jika kuota klien
+// sudah habis
+func Check(ctx context.Context, class Class, client string, limit Limit) (Result, error) {
+	return active.Take(ctx, string(class)+":"+client, limit, 0)
+}
@@ -0,0 +1,258 @@
+package routes
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/ratelimit"
+	"crud-buku-go/tracing"
+	"crud-buku-go/utils"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// apiRoutes mendaftarkan rute di bawah prefix beserta izin yang dibutuhkannya
+type apiRoutes struct {
+	router *mux.Router
+	prefix string
+}
+
+// handle mendaftarkan handler untuk method dan path, dijaga oleh requirePermission dengan
+// rate limit kelas rutenya, lalu mencatat deklarasinya untuk matriks izin
+func (a apiRoutes) handle(method, path string, perm auth.Permission, handler http.HandlerFunc) {
+	auth.DeclareRoute(auth.RouteRule{
+		Method:     method,
+		Path:       a.prefix + path,
+		Permission: perm,
+		Public:     anonymousCan(perm),
+	})
+	a.router.Handle(a.prefix+path, requirePermission(perm, routeClass(method, path), handler)).Methods(method)
+}
+
+// anonymousCan melaporkan apakah perm boleh dipakai tanpa token
+func anonymousCan(perm auth.Permission) bool {
+	return perm == "" || (perm == auth.PermBooksRead && config.App.Auth.PublicReads)
+}
+
+// apiKeyTouchInterval membatasi seberapa sering last_used_at API key ditulis ke database
+const apiKeyTouchInterval = time.Minute
+
+// requirePermission mengautentikasi pemanggil, mengambil token rate limit class, lalu
+// menolak request dengan 401 atau 403 jika pemanggil tidak boleh memakai perm. Rate limit
+// berada di antara keduanya agar penolakan karena izin juga menghabiskan kuota.
+func requirePermission(perm auth.Permission, class ratelimit.Class, next http.Handler) http.Handler {
+	return authenticate(limitRate(class, authorize(perm, next)))
+}
+
+// authenticate membaca pemanggil dari header Authorization (token akses "Bearer" atau
+// "ApiKey") atau, jika header tidak ada, dari cookie sesi hasil login OIDC, lalu
+// menyimpannya di context. Kredensial di header selalu diperiksa, termasuk pada rute yang
+// boleh diakses tanpa token, dan kegagalannya dihitung ke kuota autentikasi per IP; cookie
+// sesi yang tidak valid diperlakukan seperti tidak login.
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, credentials, ok := auth.Authorization(r)
+		if !ok {
+			principal, ok, err := sessionPrincipal(r)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "Gagal memeriksa sesi", "error", err)
+				utils.RespondWithError(w, r, http.StatusInternalServerError, "Gagal memeriksa kredensial")
+				return
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Cookie ikut terkirim otomatis oleh browser, jadi perubahan data lewat sesi
+			// hanya diterima dari halaman dengan origin yang sama
+			if !isReadMethod(r.Method) && crossSite(r) {
+				utils.RespondWithError(w, r, http.StatusForbidden, "Request lintas situs dengan cookie sesi ditolak")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+			return
+		}
+
+		if !allowAuthAttempt(w, r) {
+			return
+		}
+		var principal auth.Principal
+		var err error
+		switch scheme {
+		case auth.SchemeBearer:
+			principal, err = auth.ParseToken(credentials, auth.TokenAccess)
+		case auth.SchemeAPIKey:
+			principal, err = authenticateAPIKey(r.Context(), credentials)
+		default:
+			unauthorized(w, r, "invalid_request", "Skema Authorization tidak didukung (pakai Bearer atau ApiKey)")
+			return
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrInvalidAPIKey):
+				recordAuthFailure(r)
+				unauthorized(w, r, "invalid_token", "API key tidak valid, dicabut, atau kedaluwarsa")
+			case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrExpiredToken):
+				recordAuthFailure(r)
+				unauthorized(w, r, "invalid_token", "Token akses tidak valid atau kedaluwarsa")
+			default:
+				slog.ErrorContext(r.Context(), "Gagal memeriksa kredensial", "error", err)
+				utils.RespondWithError(w, r, http.StatusInternalServerError, "Gagal memeriksa kredensial")
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// authorize menolak pemanggil tanpa autentikasi dengan 401 kecuali perm boleh dipakai
+// tanpa token, dan pemanggil yang tidak memiliki perm dengan 403. Request yang mengubah
+// data dicatat di log audit.
+func authorize(perm auth.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			if anonymousCan(perm) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			unauthorized(w, r, "", "Autentikasi dibutuhkan")
+			return
+		}
+
+		span := tracing.SpanFromContext(r.Context())
+		span.SetAttribute("enduser.id", principal.ID())
+		if principal.APIKeyID == 0 {
+			span.SetAttribute("enduser.role", string(principal.Role))
+		}
+
+		if perm != "" && !principal.Can(perm) {
+			problem := utils.Problem{
+				Type:   utils.ProblemTypeMissingPermission,
+				Title:  "Izin tidak dimiliki",
+				Status: http.StatusForbidden,
+				Detail: "Izin " + string(perm) + " dibutuhkan",
+			}.With("missing_permission", string(perm))
+			if principal.APIKeyID != 0 {
+				problem = problem.With("scopes", principal.Scopes)
+			} else {
+				problem = problem.With("role", principal.Role)
+			}
+			utils.RespondWithProblem(w, r, problem)
+			return
+		}
+
+		if isReadMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		slog.InfoContext(r.Context(), "audit", "method", r.Method, "route", routeTemplate(r), "path", r.URL.Path,
+			"status", rw.status, "permission", string(perm))
+	})
+}
+
+// sessionPrincipal membaca pengguna dari cookie sesi yang valid. Peran di dalam token
+// bisa sudah usang, jadi pengguna dibaca ulang dari database: pengguna yang sudah dihapus
+// diperlakukan seperti tidak login dan perubahan peran langsung berlaku.
+func sessionPrincipal(r *http.Request) (auth.Principal, bool, error) {
+	token, ok := auth.SessionToken(r)
+	if !ok {
+		return auth.Principal{}, false, nil
+	}
+	principal, err := auth.ParseToken(token, auth.TokenSession)
+	if err != nil {
+		slog.DebugContext(r.Context(), "Cookie sesi diabaikan", "error", err)
+		return auth.Principal{}, false, nil
+	}
+
+	user, err := models.GetUserByID(r.Context(), principal.UserID)
+	if errors.Is(err, models.ErrUserNotFound) {
+		slog.DebugContext(r.Context(), "Cookie sesi diabaikan, pengguna sudah dihapus", "user_id", principal.UserID)
+		return auth.Principal{}, false, nil
+	}
+	if err != nil {
+		return auth.Principal{}, false, err
+	}
+	role, err := auth.ParseRole(user.Role)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Peran pengguna di database tidak dikenal", "user_id", user.ID, "error", err)
+		return auth.Principal{}, false, nil
+	}
+	principal.Username = user.Username
+	principal.Role = role
+	return principal, true, nil
+}
+
+// crossSite melaporkan apakah request dikirim dari halaman di origin lain, berdasarkan
+// header Sec-Fetch-Site atau Origin yang dikirim browser. Origin yang diizinkan CORS
+// dengan CORS_ALLOW_CREDENTIALS tidak dianggap lintas situs.
+func crossSite(r *http.Request) bool {
+	if cors.allowsCredentials(r.Header.Get("Origin")) {
+		return false
+	}
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site != "same-origin" && site != "none"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err != nil || u.Host != r.Host
+}
+
+// unauthorized mengirim 401 beserta tantangan untuk kedua skema yang didukung
+func unauthorized(w http.ResponseWriter, r *http.Request, errorCode, message string) {
+	params := `realm="crud-buku-go"`
+	if errorCode != "" {
+		params += `, error="` + errorCode + `"`
+	}
+	w.Header().Add("WWW-Authenticate", auth.SchemeBearer+" "+params)
+	w.Header().Add("WWW-Authenticate", auth.SchemeAPIKey+" "+params)
+	utils.RespondWithError(w, r, http.StatusUnauthorized, message)
+}
+
+// authenticateAPIKey mencari API key berdasarkan bagian publiknya lalu membandingkan hash-nya.
+// Waktu pemakaian terakhir dicatat paling sering sekali per apiKeyTouchInterval.
+func authenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	keyID, ok := auth.APIKeyID(key)
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidAPIKey
+	}
+	stored, err := models.GetAPIKeyByKeyID(ctx, keyID)
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		return auth.Principal{}, auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	now := time.Now()
+	if !auth.APIKeyMatches(key, stored.Hash) || !stored.Active(now) {
+		return auth.Principal{}, auth.ErrInvalidAPIKey
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		if err := models.TouchAPIKey(ctx, stored.ID, now); err != nil {
+			slog.WarnContext(ctx, "Gagal mencatat pemakaian API key", "api_key_id", stored.ID, "error", err)
+		}
+	}
+
+	scopes := make([]auth.Permission, len(stored.Scopes))
+	for i, scope := range stored.Scopes {
+		scopes[i] = auth.Permission(scope)
+	}
+	return auth.Principal{APIKeyID: stored.ID, APIKeyName: stored.Name, Scopes: scopes}, nil
+}
+
+// isReadMethod melaporkan apakah method HTTP tidak mengubah data
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
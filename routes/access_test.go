@@ -0,0 +1,110 @@
+package routes
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/ratelimit"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupSessions menyiapkan database SQLite sementara dan kunci untuk token sesi
+func setupSessions(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", config.SQLiteConnString(filepath.Join(t.TempDir(), "access.db")))
+	if err != nil {
+		t.Fatalf("membuka SQLite: %v", err)
+	}
+	if err := config.MigrateSQLite(context.Background(), db); err != nil {
+		t.Fatalf("migrasi SQLite: %v", err)
+	}
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		db.Close()
+	})
+
+	if err := auth.Configure(config.AuthConfig{
+		SigningKeys: "test:0123456789abcdef0123456789abcdef",
+		Issuer:      "crud-buku-go",
+		AccessTTL:   time.Minute,
+		RefreshTTL:  time.Hour,
+		SessionTTL:  time.Hour,
+	}); err != nil {
+		t.Fatalf("auth.Configure: %v", err)
+	}
+}
+
+// sessionCookie menerbitkan cookie sesi untuk pengguna dengan peran role
+func sessionCookie(t *testing.T, userID int, username string, role auth.Role) *http.Cookie {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := auth.IssueSession(rec, userID, username, role); err != nil {
+		t.Fatalf("IssueSession: %v", err)
+	}
+	return rec.Result().Cookies()[0]
+}
+
+func TestSessionUsesRoleFromDatabase(t *testing.T) {
+	setupSessions(t)
+	ctx := context.Background()
+	user := models.User{Username: "budi", Role: string(auth.RoleAdmin), PasswordHash: auth.NoPassword}
+	if err := models.CreateUser(ctx, &user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	cookie := sessionCookie(t, user.ID, user.Username, auth.RoleAdmin)
+	handler := requirePermission(auth.PermConfigRead, ratelimit.ClassRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(cookie *http.Cookie) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(cookie); code != http.StatusNoContent {
+		t.Fatalf("admin: status = %d, seharusnya 204", code)
+	}
+	if err := models.UpdateUserRole(ctx, user.ID, string(auth.RoleReader)); err != nil {
+		t.Fatalf("UpdateUserRole: %v", err)
+	}
+	if code := serve(cookie); code != http.StatusForbidden {
+		t.Errorf("setelah diturunkan ke reader: status = %d, seharusnya 403", code)
+	}
+	if code := serve(sessionCookie(t, user.ID+100, "hantu", auth.RoleAdmin)); code != http.StatusUnauthorized {
+		t.Errorf("pengguna yang tidak ada: status = %d, seharusnya 401", code)
+	}
+}
+
+// Statistik pool database membocorkan detail infrastruktur, jadi hanya untuk config:read
+func TestDBStatsRequiresConfigRead(t *testing.T) {
+	setupSessions(t)
+	router := SetupRoutes()
+	serve := func(role auth.Role) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/db", nil)
+		user := models.User{Username: "pengguna-" + string(role), Role: string(role), PasswordHash: auth.NoPassword}
+		if err := models.CreateUser(context.Background(), &user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		req.AddCookie(sessionCookie(t, user.ID, user.Username, role))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(auth.RoleLibrarian); code != http.StatusForbidden {
+		t.Errorf("librarian: status = %d, seharusnya 403", code)
+	}
+	if code := serve(auth.RoleAdmin); code != http.StatusOK {
+		t.Errorf("admin: status = %d, seharusnya 200", code)
+	}
+}
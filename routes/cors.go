@@ -0,0 +1,189 @@
+package routes
+
+import (
+	"crud-buku-go/config"
+	"crud-buku-go/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// corsMethods adalah method yang diperiksa saat menghitung method yang didukung sebuah path
+var corsMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// corsPolicy adalah CORSConfig yang sudah diurai menjadi pola origin dan nilai header
+type corsPolicy struct {
+	anyOrigin      bool
+	origins        []originPattern
+	credentials    bool
+	allowedHeaders string
+	exposedHeaders string
+	maxAge         string
+}
+
+// originPattern adalah satu origin yang diizinkan. Jika wildcard, host berisi akhiran
+// domain (misalnya ".example.com") yang harus didahului minimal satu label.
+type originPattern struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+// cors adalah kebijakan aktif, dibangun oleh SetupRoutes dari config.App.CORS
+var cors corsPolicy
+
+func newCORSPolicy(cfg config.CORSConfig) corsPolicy {
+	policy := corsPolicy{
+		credentials:    cfg.AllowCredentials,
+		allowedHeaders: cfg.AllowedHeaders,
+		exposedHeaders: cfg.ExposedHeaders,
+		maxAge:         strconv.Itoa(int(cfg.MaxAge.Seconds())),
+	}
+	for _, origin := range cfg.Origins() {
+		if origin == "*" {
+			policy.anyOrigin = true
+			continue
+		}
+		// Pola sudah diperiksa oleh config.Validate
+		u, err := url.Parse(origin)
+		if err != nil {
+			continue
+		}
+		pattern := originPattern{scheme: u.Scheme, host: strings.ToLower(u.Hostname()), port: u.Port()}
+		if strings.HasPrefix(pattern.host, "*.") {
+			pattern.host, pattern.wildcard = pattern.host[1:], true
+		}
+		policy.origins = append(policy.origins, pattern)
+	}
+	return policy
+}
+
+// allows melaporkan apakah origin yang dikirim browser diizinkan
+func (p corsPolicy) allows(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if p.anyOrigin {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, pattern := range p.origins {
+		if u.Scheme != pattern.scheme || u.Port() != pattern.port {
+			continue
+		}
+		if pattern.wildcard {
+			if strings.HasSuffix(host, pattern.host) && len(host) > len(pattern.host) {
+				return true
+			}
+		} else if host == pattern.host {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsCredentials melaporkan apakah origin boleh mengirim request dengan cookie sesi
+func (p corsPolicy) allowsCredentials(origin string) bool {
+	return p.credentials && p.allows(origin)
+}
+
+// setOriginHeaders menulis header yang dibutuhkan browser untuk menerima response
+// bagi origin yang diizinkan
+func (p corsPolicy) setOriginHeaders(h http.Header, origin string) {
+	if p.anyOrigin && !p.credentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// corsMiddleware menambahkan header CORS pada response untuk origin yang diizinkan.
+// Preflight tidak melewati middleware ini karena tidak ada rute OPTIONS; lihat methodNotAllowed.
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if cors.allows(origin) {
+			cors.setOriginHeaders(w.Header(), origin)
+			if cors.exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", cors.exposedHeaders)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// methodNotAllowed menangani request yang path-nya dikenal tetapi method-nya tidak.
+// Preflight CORS (OPTIONS dengan Access-Control-Request-Method) dijawab di sini dengan
+// method yang benar-benar didukung rute tersebut; request lain mendapat 405 dengan header Allow.
+func methodNotAllowed(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed := allowedMethods(router, r)
+		origin := r.Header.Get("Origin")
+		requested := r.Header.Get("Access-Control-Request-Method")
+
+		if r.Method != http.MethodOptions || origin == "" || requested == "" {
+			w.Header().Set("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+			utils.RespondWithError(w, r, http.StatusMethodNotAllowed, "Method "+r.Method+" tidak didukung untuk "+r.URL.Path)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		if !cors.allows(origin) {
+			utils.RespondWithError(w, r, http.StatusForbidden, "Origin "+origin+" tidak diizinkan")
+			return
+		}
+		if !containsMethod(allowed, requested) {
+			h.Set("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+			utils.RespondWithError(w, r, http.StatusMethodNotAllowed, "Method "+requested+" tidak didukung untuk "+r.URL.Path)
+			return
+		}
+
+		cors.setOriginHeaders(h, origin)
+		h.Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+		if cors.allowedHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", cors.allowedHeaders)
+		}
+		h.Set("Access-Control-Max-Age", cors.maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowedMethods mencari method yang cocok dengan rute untuk path request
+func allowedMethods(router *mux.Router, r *http.Request) []string {
+	var allowed []string
+	for _, method := range corsMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
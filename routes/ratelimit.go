@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/metrics"
+	"crud-buku-go/ratelimit"
+	"crud-buku-go/utils"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routeClass menentukan kelas rate limit rute: pencarian, perubahan data, atau baca
+func routeClass(method, path string) ratelimit.Class {
+	switch {
+	case strings.HasSuffix(path, "/search") || strings.HasSuffix(path, "/suggest"):
+		return ratelimit.ClassSearch
+	case !isReadMethod(method):
+		return ratelimit.ClassWrite
+	}
+	return ratelimit.ClassRead
+}
+
+// limitRate mengambil satu token dari bucket pemanggil untuk kelas rute dan menolak
+// request dengan 429 jika kuotanya habis. Header RateLimit-* dikirim di setiap response.
+// Jika store gagal, request tetap dilayani agar gangguan store tidak mematikan API.
+func limitRate(class ratelimit.Class, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := ratelimit.LimitFor(class)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := clientKey(r)
+		result, err := ratelimit.Take(r.Context(), class, client, limit)
+		if err != nil {
+			slog.WarnContext(r.Context(), "Rate limit dilewati karena store gagal", "class", class, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		setRateLimitHeaders(w, limit, result)
+		if !result.Allowed {
+			rejectRateLimited(w, r, class, client, result)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowAuthAttempt memeriksa kuota autentikasi gagal milik IP pemanggil sebelum kredensial
+// di header diperiksa, dan menolak request dengan 429 jika kuotanya sudah habis. Dengan
+// begitu kredensial yang ditebak-tebak tidak bisa memicu lookup database tanpa batas.
+func allowAuthAttempt(w http.ResponseWriter, r *http.Request) bool {
+	limit, ok := ratelimit.LimitFor(ratelimit.ClassAuth)
+	if !ok {
+		return true
+	}
+	client := "ip:" + clientIP(r)
+	result, err := ratelimit.Check(r.Context(), ratelimit.ClassAuth, client, limit)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Rate limit dilewati karena store gagal", "class", ratelimit.ClassAuth, "error", err)
+		return true
+	}
+	if result.Allowed {
+		return true
+	}
+	setRateLimitHeaders(w, limit, result)
+	rejectRateLimited(w, r, ratelimit.ClassAuth, client, result)
+	return false
+}
+
+// recordAuthFailure memakai satu token kuota autentikasi gagal milik IP pemanggil
+func recordAuthFailure(r *http.Request) {
+	limit, ok := ratelimit.LimitFor(ratelimit.ClassAuth)
+	if !ok {
+		return
+	}
+	if _, err := ratelimit.Take(r.Context(), ratelimit.ClassAuth, "ip:"+clientIP(r), limit); err != nil {
+		slog.WarnContext(r.Context(), "Gagal mencatat autentikasi gagal", "error", err)
+	}
+}
+
+// setRateLimitHeaders mengirim kuota dan sisa kuota bucket dalam header RateLimit-*
+func setRateLimitHeaders(w http.ResponseWriter, limit ratelimit.Limit, result ratelimit.Result) {
+	h := w.Header()
+	h.Set("RateLimit-Policy", limit.Policy())
+	h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+// rejectRateLimited mengirim 429 beserta Retry-After untuk kuota class yang habis
+func rejectRateLimited(w http.ResponseWriter, r *http.Request, class ratelimit.Class, client string, result ratelimit.Result) {
+	retryAfter := ceilSeconds(result.RetryAfter)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	metrics.HTTPRateLimited.Inc(string(class))
+	slog.WarnContext(r.Context(), "Request ditolak rate limit", "class", class, "client", client,
+		"retry_after", retryAfter)
+	utils.RespondWithProblem(w, r, utils.Problem{
+		Type:   utils.ProblemTypeRateLimited,
+		Title:  "Terlalu banyak request",
+		Status: http.StatusTooManyRequests,
+		Detail: "Kuota " + string(class) + " habis, coba lagi dalam " + strconv.Itoa(retryAfter) + " detik",
+	}.With("retry_after", retryAfter))
+}
+
+// clientKey mengembalikan identitas pemanggil untuk bucket rate limit: API key atau
+// pengguna yang terautentikasi, atau alamat IP untuk pemanggil anonim
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return principal.ID()
+	}
+	return "ip:" + clientIP(r)
+}
+
+// clientIP mengambil alamat IP klien. X-Forwarded-For hanya dipercaya jika
+// RATE_LIMIT_TRUST_FORWARDED_FOR aktif; alamat terakhir adalah yang ditambahkan proxy.
+func clientIP(r *http.Request) string {
+	if config.App.RateLimit.TrustForwardedFor {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds membulatkan durasi ke atas dalam detik, sesuai format header Retry-After
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"context"
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// useRateLimit mengaktifkan rate limit di memori dengan kuota kecil selama test berjalan
+func useRateLimit(t *testing.T, read, authFailures string) {
+	t.Helper()
+	if err := ratelimit.Configure(config.RateLimitConfig{
+		Enabled: true, Store: ratelimit.StoreMemory, Search: "off", Write: "off", Read: read, Auth: authFailures,
+	}); err != nil {
+		t.Fatalf("ratelimit.Configure: %v", err)
+	}
+	t.Cleanup(func() { ratelimit.Configure(config.RateLimitConfig{}) })
+}
+
+func serveStatuses(handler http.Handler, n int, prepare func(*http.Request)) []int {
+	statuses := make([]int, n)
+	for i := range statuses {
+		req := httptest.NewRequest(http.MethodGet, "/api/books", nil)
+		req.RemoteAddr = "203.0.113.9:4321"
+		prepare(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		statuses[i] = rec.Code
+	}
+	return statuses
+}
+
+func TestFailedAuthenticationIsRateLimitedPerIP(t *testing.T) {
+	useRateLimit(t, "off", "2/1m")
+	handler := requirePermission(auth.PermBooksRead, ratelimit.ClassRead, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	got := serveStatuses(handler, 3, func(r *http.Request) {
+		r.Header.Set("Authorization", auth.SchemeAPIKey+" bukan-api-key")
+	})
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("status = %v, seharusnya %v", got, want)
+		}
+	}
+}
+
+func TestForbiddenRequestsCountAgainstRouteQuota(t *testing.T) {
+	setupSessions(t)
+	useRateLimit(t, "2/1m", "off")
+	user := models.User{Username: "siti", Role: string(auth.RoleReader), PasswordHash: auth.NoPassword}
+	if err := models.CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	cookie := sessionCookie(t, user.ID, user.Username, auth.RoleReader)
+	handler := requirePermission(auth.PermConfigRead, ratelimit.ClassRead, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	got := serveStatuses(handler, 3, func(r *http.Request) { r.AddCookie(cookie) })
+	want := []int{http.StatusForbidden, http.StatusForbidden, http.StatusTooManyRequests}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("status = %v, seharusnya %v", got, want)
+		}
+	}
+}
@@ -1,79 +1,190 @@
 package routes
 
 import (
+	"crud-buku-go/auth"
+	"crud-buku-go/config"
 	"crud-buku-go/controllers"
+	"crud-buku-go/lifecycle"
+	"crud-buku-go/logging"
+	"crud-buku-go/metrics"
+	"crud-buku-go/tracing"
+	"crud-buku-go/utils"
 	_ "crud-buku-go/docs"
 	"fmt"
-	"log"
-    "time"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
 func SetupRoutes() *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
+	cors = newCORSPolicy(config.App.CORS)
+	router.MethodNotAllowedHandler = requestIDMiddleware(methodNotAllowed(router))
+	router.NotFoundHandler = requestIDMiddleware(http.HandlerFunc(notFound))
 
+	router.Use(requestIDMiddleware)
 	router.Use(corsMiddleware)
+	router.Use(tracingMiddleware)
+	router.Use(metricsMiddleware)
 	router.Use(loggingMiddleware)
+	router.Use(drainMiddleware)
 
 	router.PathPrefix("/api/doc/").Handler(httpSwagger.WrapHandler)
 
 	router.HandleFunc("/", homeHandler).Methods("GET")
 
+	// Health routes untuk probe orchestrator
+	router.HandleFunc("/healthz", controllers.LivenessHandler).Methods("GET")
+	router.HandleFunc("/readyz", controllers.ReadinessHandler).Methods("GET")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	// Rute API dideklarasikan bersama izin yang dibutuhkannya (lihat auth.Matrix).
+	// Izin kosong berarti rute publik; books:read publik jika AUTH_PUBLIC_READS aktif.
+	api := apiRoutes{router: router, prefix: "/api"}
+
+	// Auth routes
+	api.handle("POST", "/auth/login", "", controllers.LoginHandler)
+	api.handle("POST", "/auth/refresh", "", controllers.RefreshHandler)
+	api.handle("POST", "/auth/logout", "", controllers.LogoutHandler)
+	api.handle("GET", "/auth/me", "", controllers.MeHandler)
+	api.handle("GET", "/auth/oidc/login", "", controllers.OIDCLoginHandler)
+	api.handle("GET", "/auth/oidc/callback", "", controllers.OIDCCallbackHandler)
+
 	// Book routes
-	bookRouter := router.PathPrefix("/api/books").Subrouter()
-	bookRouter.HandleFunc("", controllers.GetBooksHandler).Methods("GET")
-	bookRouter.HandleFunc("", controllers.CreateBookHandler).Methods("POST")
-	bookRouter.HandleFunc("/search", controllers.SearchBooksHandler).Methods("GET")
-	bookRouter.HandleFunc("/{id}", controllers.GetBookHandler).Methods("GET")
-	bookRouter.HandleFunc("/{id}", controllers.UpdateBookHandler).Methods("PUT")
-	bookRouter.HandleFunc("/{id}", controllers.PatchBookHandler).Methods("PATCH")
-	bookRouter.HandleFunc("/{id}", controllers.DeleteBookHandler).Methods("DELETE")
-
-	log.Println("Rute Swagger UI telah diinisialisasi di /api/doc/")
-	log.Println("Rute API telah diinisialisasi.")
+	api.handle("GET", "/books", auth.PermBooksRead, controllers.GetBooksHandler)
+	api.handle("POST", "/books", auth.PermBooksWrite, controllers.CreateBookHandler)
+	api.handle("GET", "/books/search", auth.PermBooksRead, controllers.SearchBooksHandler)
+	api.handle("GET", "/books/suggest", auth.PermBooksRead, controllers.SuggestBooksHandler)
+	api.handle("GET", "/books/{id}", auth.PermBooksRead, controllers.GetBookHandler)
+	api.handle("PUT", "/books/{id}", auth.PermBooksWrite, controllers.UpdateBookHandler)
+	api.handle("PATCH", "/books/{id}", auth.PermBooksWrite, controllers.PatchBookHandler)
+	api.handle("DELETE", "/books/{id}", auth.PermBooksWrite, controllers.DeleteBookHandler)
+
+	// Stats routes
+	api.handle("GET", "/stats", auth.PermBooksRead, controllers.GetStatsHandler)
+	api.handle("GET", "/stats/cache", auth.PermBooksRead, controllers.GetCacheStatsHandler)
+	api.handle("GET", "/stats/db", auth.PermConfigRead, controllers.GetDBStatsHandler)
+
+	// Admin routes
+	api.handle("GET", "/admin/config", auth.PermConfigRead, controllers.GetConfigHandler)
+	api.handle("GET", "/admin/permissions", auth.PermUsersManage, controllers.GetPermissionsHandler)
+	api.handle("GET", "/admin/users", auth.PermUsersManage, controllers.ListUsersHandler)
+	api.handle("PUT", "/admin/users/{id}/role", auth.PermUsersManage, controllers.UpdateUserRoleHandler)
+	api.handle("GET", "/admin/api-keys", auth.PermAPIKeysManage, controllers.ListAPIKeysHandler)
+	api.handle("POST", "/admin/api-keys", auth.PermAPIKeysManage, controllers.CreateAPIKeyHandler)
+	api.handle("DELETE", "/admin/api-keys/{id}", auth.PermAPIKeysManage, controllers.RevokeAPIKeyHandler)
+
+	slog.Info("Rute API telah diinisialisasi", "swagger", "/api/doc/")
 	return router
 }
 
+// drainMiddleware meminta klien menutup koneksi keep-alive selama shutdown
+// agar request berikutnya diarahkan ke instance lain
+func drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !lifecycle.Ready() {
+			w.Header().Set("Connection", "close")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeTemplate mengembalikan template rute mux untuk request, misalnya /api/books/{id}
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
+
+// tracingMiddleware membuat span server untuk setiap request, melanjutkan trace
+// dari header traceparent jika ada
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.Start(ctx, r.Method+" "+route, tracing.KindServer)
+		defer span.End()
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("url.path", r.URL.Path)
+		span.SetAttribute("http.request_id", logging.RequestID(ctx))
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		span.SetAttribute("http.response.status_code", rw.status)
+		if rw.status >= 500 {
+			span.RecordError(fmt.Errorf("HTTP %d", rw.status))
+		}
+	})
+}
+
+// metricsMiddleware mencatat jumlah, lama, dan ukuran response setiap request,
+// dengan label template rute mux agar /api/books/1 dan /api/books/2 tergabung
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		metrics.ObserveRequest(r.Method, routeTemplate(r), rw.status, rw.size, time.Since(start))
+	})
+}
+
+// quietPaths tidak dicatat oleh loggingMiddleware karena dipanggil terus-menerus oleh probe
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// requestIDMiddleware memakai X-Request-ID dari klien jika valid atau membuat yang baru,
+// menyimpannya di context agar ikut di setiap baris log, dan mengirimkannya kembali di response
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
 // logging
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if quietPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		start := time.Now()
-		
-		// Log request details
-		log.Printf("📥 [%s] %s %s from %s", r.Method, r.RequestURI, r.Proto, r.RemoteAddr)
-		
+		ctx := r.Context()
+		slog.DebugContext(ctx, "request dimulai", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+
 		// Wrap the response writer to capture status code
 		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
-		
-		// Process the request
 		next.ServeHTTP(rw, r)
-		
-		// Calculate response time
-		duration := time.Since(start)
-		
-		// Log response details
-		log.Printf("📤 [%d] %s %s completed in %v (size: %d bytes)", 
-			rw.status, 
-			r.Method, 
-			r.RequestURI, 
-			duration,
-			rw.size,
+
+		level := slog.LevelInfo
+		if rw.status >= 500 {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "request selesai",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", routeTemplate(r),
+			"status", rw.status,
+			"size", rw.size,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"proto", r.Proto,
+			"remote_addr", r.RemoteAddr,
 		)
 	})
 }
@@ -96,10 +207,15 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// notFound menjawab path yang tidak cocok dengan rute mana pun
+func notFound(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithError(w, r, http.StatusNotFound, "Path "+r.URL.Path+" tidak ditemukan")
+}
+
 func homeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	fmt.Fprintf(w, `<!DOCTYPE html>
+	fmt.Fprint(w, `<!DOCTYPE html>
 <html lang="id">
 <head>
     <meta charset="UTF-8">
@@ -0,0 +1,200 @@
+package search
+
+import (
+	"context"
+	"crud-buku-go/models"
+	"crud-buku-go/searchql"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Bit field tempat sebuah token ditemukan pada satu buku
+const (
+	inTitle = 1 << iota
+	inAuthor
+	inYear
+)
+
+// MemorySearcher adalah inverted index murni Go yang dibangun dari seluruh buku.
+// Indeks dibangun ulang secara malas pada pencarian pertama setelah data buku berubah.
+type MemorySearcher struct {
+	mu       sync.RWMutex
+	stale    bool
+	books    map[int]models.Book
+	postings map[string]map[int]int
+}
+
+// NewMemorySearcher membuat indeks kosong yang akan dibangun saat pencarian pertama
+// dan ditandai usang setiap kali data buku berubah.
+func NewMemorySearcher() *MemorySearcher {
+	s := &MemorySearcher{stale: true}
+	models.OnBooksChanged(s.Invalidate)
+	return s
+}
+
+// Name mengembalikan nama backend
+func (s *MemorySearcher) Name() string {
+	return BackendMemory
+}
+
+// Invalidate menandai indeks usang sehingga dibangun ulang pada pencarian berikutnya
+func (s *MemorySearcher) Invalidate() {
+	s.mu.Lock()
+	s.stale = true
+	s.mu.Unlock()
+}
+
+func (s *MemorySearcher) rebuild(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stale {
+		return nil
+	}
+
+	all, err := models.GetAllBooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	books := make(map[int]models.Book, len(all))
+	postings := make(map[string]map[int]int)
+	add := func(text string, id, field int) {
+		for _, token := range tokenize(text) {
+			if postings[token] == nil {
+				postings[token] = make(map[int]int)
+			}
+			postings[token][id] |= field
+		}
+	}
+	for _, book := range all {
+		books[book.ID] = book
+		add(book.Title, book.ID, inTitle)
+		add(book.Author, book.ID, inAuthor)
+		add(strconv.Itoa(book.Year), book.ID, inYear)
+	}
+
+	s.books = books
+	s.postings = postings
+	s.stale = false
+	return nil
+}
+
+// Search mencari buku yang memuat semua token query di judul, penulis, atau tahun
+func (s *MemorySearcher) Search(ctx context.Context, query string, opts Options) ([]models.SearchResult, error) {
+	if err := s.rebuild(ctx); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tokens := tokenize(query)
+	if len(tokens) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Hitung di field mana saja setiap token cocok, untuk buku yang memuat semua token
+	matches := make(map[int][]int)
+	for i, token := range tokens {
+		for id, fields := range s.postings[token] {
+			if i == 0 {
+				matches[id] = make([]int, len(tokens))
+			} else if _, ok := matches[id]; !ok {
+				continue
+			}
+			matches[id][i] = fields
+		}
+		for id, fields := range matches {
+			if fields[i] == 0 {
+				delete(matches, id)
+			}
+		}
+	}
+
+	relevance := opts.Relevance
+	currentYear := float64(time.Now().Year())
+	results := make([]models.SearchResult, 0, len(matches))
+	for id, fields := range matches {
+		var titleHits, authorHits, yearHits float64
+		for _, f := range fields {
+			if f&inTitle != 0 {
+				titleHits++
+			}
+			if f&inAuthor != 0 {
+				authorHits++
+			}
+			if f&inYear != 0 {
+				yearHits++
+			}
+		}
+		n := float64(len(tokens))
+		book := s.books[id]
+		breakdown := models.ScoreExplanation{
+			TitleRank:     relevance.TitleBoost * titleHits / n,
+			AuthorRank:    relevance.AuthorBoost * authorHits / n,
+			RecencyWeight: relevance.RecencyWeight,
+		}
+		breakdown.TextRank = breakdown.TitleRank + breakdown.AuthorRank + relevance.YearBoost*yearHits/n
+		age := currentYear - float64(book.Year)
+		if age < 0 {
+			age = 0
+		}
+		breakdown.Recency = 1 / (1 + age/relevance.RecencyScale)
+		breakdown.Score = breakdown.TextRank * (1 + relevance.RecencyWeight*breakdown.Recency)
+
+		result := models.SearchResult{Book: book, Score: breakdown.Score}
+		if opts.Explain {
+			breakdown.Boosts = relevance.Boosts()
+			result.Explain = &breakdown
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Title < results[j].Title
+	})
+	return results, nil
+}
+
+// SearchQuery mencocokkan query berfield ke setiap buku di indeks
+func (s *MemorySearcher) SearchQuery(ctx context.Context, q *searchql.Query, _ Options) ([]models.SearchResult, error) {
+	if err := s.rebuild(ctx); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := []models.SearchResult{}
+	for _, book := range s.books {
+		if q.Match(book.Title, book.Author, book.Year) {
+			results = append(results, models.SearchResult{Book: book})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Title != results[j].Title {
+			return results[i].Title < results[j].Title
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+	"crud-buku-go/models"
+	"crud-buku-go/searchql"
+)
+
+// PostgresSearcher memakai full-text search PostgreSQL (kolom search_vector dari migrasi 002/004)
+type PostgresSearcher struct{}
+
+// Name mengembalikan nama backend
+func (PostgresSearcher) Name() string {
+	return BackendPostgres
+}
+
+// Search menjalankan full-text search berbobot
+func (PostgresSearcher) Search(ctx context.Context, query string, opts Options) ([]models.SearchResult, error) {
+	return models.FullTextSearchBooks(ctx, query, opts.Relevance, opts.Explain)
+}
+
+// SearchQuery menjalankan query berfield sebagai klausa SQL
+func (PostgresSearcher) SearchQuery(ctx context.Context, q *searchql.Query, _ Options) ([]models.SearchResult, error) {
+	return queryBooks(ctx, q)
+}
+
+// LikeSearcher memakai pencocokan LIKE sederhana dan tidak membutuhkan migrasi full-text
+type LikeSearcher struct{}
+
+// Name mengembalikan nama backend
+func (LikeSearcher) Name() string {
+	return BackendLike
+}
+
+// Search menjalankan pencarian LIKE; opsi relevansi dan explain tidak berlaku
+func (LikeSearcher) Search(ctx context.Context, query string, _ Options) ([]models.SearchResult, error) {
+	return models.LikeSearchBooks(ctx, query)
+}
+
+// SearchQuery menjalankan query berfield sebagai klausa SQL
+func (LikeSearcher) SearchQuery(ctx context.Context, q *searchql.Query, _ Options) ([]models.SearchResult, error) {
+	return queryBooks(ctx, q)
+}
+
+// queryBooks mengompilasi q menjadi klausa WHERE terparameterisasi untuk backend yang
+// membaca langsung dari tabel books
+func queryBooks(ctx context.Context, q *searchql.Query) ([]models.SearchResult, error) {
+	where, args := searchql.Compile(q, 1)
+	books, err := models.QueryBooks(ctx, where, args)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]models.SearchResult, 0, len(books))
+	for _, book := range books {
+		results = append(results, models.SearchResult{Book: book})
+	}
+	return results, nil
+}
@@ -0,0 +1,118 @@
+// Package search menyediakan backend pencarian buku yang bisa dipilih lewat konfigurasi.
+// Setiap backend melaporkan kesalahannya sendiri; tidak ada fallback diam-diam antar backend.
+package search
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/searchql"
+	"crud-buku-go/tracing"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Nama backend yang didukung
+const (
+	BackendPostgres = "postgres"
+	BackendLike     = "like"
+	BackendMemory   = "memory"
+	BackendSQLite   = "sqlite"
+)
+
+// Options mengatur cara sebuah pencarian dijalankan
+type Options struct {
+	Relevance models.RelevanceConfig
+	Explain   bool
+}
+
+// Searcher adalah backend pencarian buku
+type Searcher interface {
+	// Name mengembalikan nama backend untuk dilaporkan di response
+	Name() string
+	// Search mengembalikan hasil yang sudah diurutkan berdasarkan relevansi
+	Search(ctx context.Context, query string, opts Options) ([]models.SearchResult, error)
+	// SearchQuery menjalankan query berfield hasil searchql.Parse. Hasilnya tidak
+	// memiliki skor dan diurutkan berdasarkan judul lalu ID.
+	SearchQuery(ctx context.Context, q *searchql.Query, opts Options) ([]models.SearchResult, error)
+}
+
+// BackendError membungkus kesalahan dari backend pencarian beserta nama backend-nya
+type BackendError struct {
+	Backend string
+	Err     error
+}
+
+func (e *BackendError) Error() string {
+	return fmt.Sprintf("backend pencarian %q gagal: %v", e.Backend, e.Err)
+}
+
+func (e *BackendError) Unwrap() error {
+	return e.Err
+}
+
+var backends = map[string]func() Searcher{
+	BackendPostgres: func() Searcher { return PostgresSearcher{} },
+	BackendLike:     func() Searcher { return LikeSearcher{} },
+	BackendMemory:   func() Searcher { return NewMemorySearcher() },
+	BackendSQLite:   func() Searcher { return SQLiteSearcher{} },
+}
+
+// Active adalah backend yang dipakai oleh handler pencarian
+var Active Searcher = PostgresSearcher{}
+
+// New membuat backend berdasarkan nama
+func New(name string) (Searcher, error) {
+	factory, ok := backends[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(backends))
+		for n := range backends {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("backend pencarian %q tidak dikenal (pilihan: %s)", name, strings.Join(names, ", "))
+	}
+	return factory(), nil
+}
+
+// Configure memilih backend aktif dari konfigurasi pencarian (SEARCH_BACKEND)
+func Configure() error {
+	searcher, err := New(config.App.Search.Backend)
+	if err != nil {
+		return err
+	}
+	Active = searcher
+	slog.Info("Backend pencarian aktif", "backend", searcher.Name())
+	return nil
+}
+
+// Run menjalankan pencarian pada backend s dan membungkus kesalahannya dengan BackendError.
+// Setiap pencarian tercatat sebagai span dengan nama backend-nya.
+func Run(ctx context.Context, s Searcher, query string, opts Options) ([]models.SearchResult, error) {
+	return run(ctx, s, func(ctx context.Context) ([]models.SearchResult, error) {
+		return s.Search(ctx, query, opts)
+	})
+}
+
+// RunQuery seperti Run, tetapi untuk query berfield
+func RunQuery(ctx context.Context, s Searcher, q *searchql.Query, opts Options) ([]models.SearchResult, error) {
+	return run(ctx, s, func(ctx context.Context) ([]models.SearchResult, error) {
+		return s.SearchQuery(ctx, q, opts)
+	})
+}
+
+func run(ctx context.Context, s Searcher, search func(context.Context) ([]models.SearchResult, error)) ([]models.SearchResult, error) {
+	ctx, span := tracing.Start(ctx, "search "+s.Name(), tracing.KindInternal)
+	defer span.End()
+	span.SetAttribute("search.backend", s.Name())
+
+	results, err := search(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, &BackendError{Backend: s.Name(), Err: err}
+	}
+	span.SetAttribute("search.results", len(results))
+	return results, nil
+}
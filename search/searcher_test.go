@@ -0,0 +1,82 @@
+package search_test
+
+import (
+	"context"
+	"crud-buku-go/config"
+	"crud-buku-go/models"
+	"crud-buku-go/search"
+	"crud-buku-go/searchql"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Backend memori harus memberi hasil yang sama dengan backend SQL untuk query berfield
+func TestSearchQueryBackendsAgree(t *testing.T) {
+	db, err := sql.Open("sqlite", config.SQLiteConnString(filepath.Join(t.TempDir(), "search.db")))
+	if err != nil {
+		t.Fatalf("membuka SQLite: %v", err)
+	}
+	ctx := context.Background()
+	if err := config.MigrateSQLite(ctx, db); err != nil {
+		t.Fatalf("migrasi SQLite: %v", err)
+	}
+	previousDB, previousStore := config.DB, models.Store
+	config.DB, models.Store = db, models.SQLiteStore{}
+	t.Cleanup(func() {
+		config.DB, models.Store = previousDB, previousStore
+		db.Close()
+	})
+
+	for _, book := range []models.Book{
+		{Title: "Supernova: Ksatria, Puteri, dan Bintang Jatuh", Author: "Dee Lestari", Year: 2001},
+		{Title: "Filosofi Kopi", Author: "Dee Lestari", Year: 2006},
+		{Title: "Perahu Kertas", Author: "Dee Lestari", Year: 2009},
+		{Title: "Laskar Pelangi", Author: "Andrea Hirata", Year: 2005},
+		{Title: "100% Indonesia", Author: "Anonim", Year: 2012},
+	} {
+		if err := models.Store.CreateBook(ctx, &book); err != nil {
+			t.Fatalf("CreateBook: %v", err)
+		}
+	}
+
+	backends := []search.Searcher{search.SQLiteSearcher{}, search.LikeSearcher{}, search.NewMemorySearcher()}
+	for _, tt := range []struct {
+		input string
+		hits  int
+	}{
+		{`author:"Dee Lestari" year:2005..2012 -title:filosofi`, 1},
+		{`kopi OR pelangi`, 2},
+		{`(lestari OR hirata) year:..2005`, 2},
+		{`"perahu kertas"`, 1},
+		{`2005`, 1},
+		{`title:100%`, 1},
+		{`Dune:Messiah`, 0},
+	} {
+		input := tt.input
+		q, err := searchql.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		var want []int
+		for i, backend := range backends {
+			results, err := search.RunQuery(ctx, backend, q, search.Options{})
+			if err != nil {
+				t.Fatalf("%s: RunQuery(%q): %v", backend.Name(), input, err)
+			}
+			ids := []int{}
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			if i == 0 {
+				want = ids
+				if len(ids) != tt.hits {
+					t.Errorf("%s: RunQuery(%q) = %v, seharusnya %d hasil", backend.Name(), input, ids, tt.hits)
+				}
+			} else if !reflect.DeepEqual(ids, want) {
+				t.Errorf("%s: RunQuery(%q) = %v, %s memberi %v", backend.Name(), input, ids, backends[0].Name(), want)
+			}
+		}
+	}
+}
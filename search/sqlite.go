@@ -0,0 +1,25 @@
+package search
+
+import (
+	"context"
+	"crud-buku-go/models"
+	"crud-buku-go/searchql"
+)
+
+// SQLiteSearcher memakai indeks FTS5 SQLite (migrasi migrations/sqlite/002_books_fts.sql)
+type SQLiteSearcher struct{}
+
+// Name mengembalikan nama backend
+func (SQLiteSearcher) Name() string {
+	return BackendSQLite
+}
+
+// Search menjalankan pencarian FTS5 dengan peringkat bm25 berbobot
+func (SQLiteSearcher) Search(ctx context.Context, query string, opts Options) ([]models.SearchResult, error) {
+	return models.FTS5SearchBooks(ctx, query, opts.Relevance, opts.Explain)
+}
+
+// SearchQuery menjalankan query berfield sebagai klausa SQL
+func (SQLiteSearcher) SearchQuery(ctx context.Context, q *searchql.Query, _ Options) ([]models.SearchResult, error) {
+	return queryBooks(ctx, q)
+}
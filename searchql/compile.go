@@ -0,0 +1,94 @@
+package searchql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Compile menerjemahkan query menjadi klausa WHERE SQL yang terparameterisasi.
+// Placeholder dimulai dari $firstArg sehingga klausa bisa digabung dengan parameter lain.
+// Nilai dari pengguna tidak pernah disisipkan langsung ke dalam string SQL.
+func Compile(q *Query, firstArg int) (string, []interface{}) {
+	c := &compiler{next: firstArg}
+	where := c.compile(q.Root)
+	return where, c.args
+}
+
+type compiler struct {
+	args []interface{}
+	next int
+}
+
+func (c *compiler) bind(value interface{}) string {
+	c.args = append(c.args, value)
+	placeholder := "$" + strconv.Itoa(c.next)
+	c.next++
+	return placeholder
+}
+
+func (c *compiler) compile(n Node) string {
+	switch n := n.(type) {
+	case And:
+		return c.join(n.Terms, " AND ")
+	case Or:
+		return c.join(n.Terms, " OR ")
+	case Not:
+		return "NOT " + c.compile(n.Term)
+	case Range:
+		var conds []string
+		if n.From != nil {
+			conds = append(conds, "year >= "+c.bind(*n.From))
+		}
+		if n.To != nil {
+			conds = append(conds, "year <= "+c.bind(*n.To))
+		}
+		return "(" + strings.Join(conds, " AND ") + ")"
+	case Term:
+		return c.compileTerm(n)
+	default:
+		panic(fmt.Sprintf("searchql: node %T tidak dikenal", n))
+	}
+}
+
+func (c *compiler) join(terms []Node, sep string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = c.compile(term)
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+func (c *compiler) compileTerm(t Term) string {
+	switch t.Field {
+	case FieldYear:
+		year, _ := strconv.Atoi(t.Value) // sudah divalidasi saat parsing
+		return "(year = " + c.bind(year) + ")"
+	case FieldTitle, FieldAuthor:
+		return "(" + likeClause(t.Field, c.bind(likePattern(t.Value))) + ")"
+	}
+
+	pattern := c.bind(likePattern(t.Value))
+	clause := likeClause(FieldTitle, pattern) + " OR " + likeClause(FieldAuthor, pattern)
+	if year, err := strconv.Atoi(t.Value); err == nil && !t.Phrase {
+		clause += " OR year = " + c.bind(year)
+	}
+	return "(" + clause + ")"
+}
+
+// likeClause memakai LOWER(...) LIKE dengan ESCAPE eksplisit agar portabel antar database
+// dan bisa memanfaatkan indeks LOWER(...) text_pattern_ops.
+func likeClause(column, placeholder string) string {
+	return "LOWER(" + column + ") LIKE LOWER(" + placeholder + `) ESCAPE '\'`
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike meloloskan karakter wildcard LIKE (%, _ dan \) pada nilai dari pengguna
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+func likePattern(value string) string {
+	return "%" + EscapeLike(value) + "%"
+}
@@ -0,0 +1,59 @@
+package searchql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Match melaporkan apakah buku dengan judul, penulis, dan tahun tersebut cocok dengan q.
+// Semantiknya sama dengan klausa hasil Compile, untuk backend yang tidak memakai SQL.
+func (q *Query) Match(title, author string, year int) bool {
+	return match(q.Root, strings.ToLower(title), strings.ToLower(author), year)
+}
+
+func match(n Node, title, author string, year int) bool {
+	switch n := n.(type) {
+	case And:
+		for _, term := range n.Terms {
+			if !match(term, title, author, year) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, term := range n.Terms {
+			if match(term, title, author, year) {
+				return true
+			}
+		}
+		return false
+	case Not:
+		return !match(n.Term, title, author, year)
+	case Range:
+		return (n.From == nil || year >= *n.From) && (n.To == nil || year <= *n.To)
+	case Term:
+		return matchTerm(n, title, author, year)
+	default:
+		panic(fmt.Sprintf("searchql: node %T tidak dikenal", n))
+	}
+}
+
+func matchTerm(t Term, title, author string, year int) bool {
+	value := strings.ToLower(t.Value)
+	switch t.Field {
+	case FieldYear:
+		want, _ := strconv.Atoi(t.Value) // sudah divalidasi saat parsing
+		return year == want
+	case FieldTitle:
+		return strings.Contains(title, value)
+	case FieldAuthor:
+		return strings.Contains(author, value)
+	}
+
+	if strings.Contains(title, value) || strings.Contains(author, value) {
+		return true
+	}
+	want, err := strconv.Atoi(t.Value)
+	return err == nil && !t.Phrase && year == want
+}
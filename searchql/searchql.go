@@ -0,0 +1,358 @@
+// Package searchql mengurai bahasa query berfield untuk endpoint pencarian buku,
+// misalnya `author:"Dee Lestari" year:2005..2012 -title:filosofi`.
+//
+// Sintaks yang didukung:
+//
+//	kata            kata bebas, dicocokkan ke judul atau penulis (atau tahun jika angka)
+//	"frasa kata"    frasa yang harus muncul utuh
+//	field:nilai     membatasi ke satu field (title, author, year); nama lain dibaca sebagai kata
+//	year:2005..2012 rentang tahun, batas boleh dikosongkan (year:2005.. atau year:..2012)
+//	-term           negasi
+//	a OR b          salah satu harus cocok, term yang berdampingan digabung dengan AND
+//	( ... )         pengelompokan
+package searchql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field yang dikenali oleh parser
+const (
+	FieldTitle  = "title"
+	FieldAuthor = "author"
+	FieldYear   = "year"
+)
+
+var knownFields = map[string]bool{FieldTitle: true, FieldAuthor: true, FieldYear: true}
+
+// SyntaxError menandakan query yang tidak valid beserta posisi (kolom, mulai dari 1) kesalahannya
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("kesalahan sintaks query pada posisi %d: %s", e.Pos, e.Msg)
+}
+
+// Node adalah simpul pada pohon query hasil parsing
+type Node interface {
+	node()
+}
+
+// And cocok jika semua term cocok
+type And struct {
+	Terms []Node
+}
+
+// Or cocok jika salah satu term cocok
+type Or struct {
+	Terms []Node
+}
+
+// Not membalik hasil term di dalamnya
+type Not struct {
+	Term Node
+}
+
+// Term mencocokkan nilai teks (atau tahun) pada satu field, atau judul/penulis jika Field kosong
+type Term struct {
+	Field  string
+	Value  string
+	Phrase bool
+	Pos    int
+}
+
+// Range mencocokkan tahun dalam rentang inklusif; batas nil berarti terbuka
+type Range struct {
+	Field string
+	From  *int
+	To    *int
+	Pos   int
+}
+
+func (And) node()   {}
+func (Or) node()    {}
+func (Not) node()   {}
+func (Term) node()  {}
+func (Range) node() {}
+
+// Query adalah hasil parsing string query
+type Query struct {
+	Root  Node
+	plain bool
+}
+
+// Plain bernilai true jika query hanya berisi kata bebas tanpa field, frasa,
+// negasi, OR, atau rentang, sehingga bisa dilayani oleh pencarian teks biasa.
+func (q *Query) Plain() bool {
+	return q.plain
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokField
+	tokMinus
+	tokOr
+	tokLParen
+	tokRParen
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case r == '-' && (i == 0 || isBoundary(runes[i-1])) && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: pos})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, &SyntaxError{Pos: pos, Msg: "tanda kutip tidak ditutup"}
+			}
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			if phrase == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: "frasa kosong"}
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: phrase, pos: pos})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			word := string(runes[i:end])
+			// "field:" hanya dianggap prefix field jika langsung diikuti nilai,
+			// sehingga teks seperti "Supernova: Ksatria" tetap menjadi kata biasa.
+			// Nama yang bukan field (misalnya "Dune:Messiah") juga dibaca sebagai kata
+			// biasa; hanya `nama:"frasa"` yang jelas bermaksud memakai field yang ditolak.
+			if colon := strings.IndexRune(word, ':'); colon > 0 && isFieldName(word[:colon]) &&
+				(colon < len(word)-1 || (end < len(runes) && runes[end] == '"')) {
+				name := strings.ToLower(word[:colon])
+				quoted := colon == len(word)-1
+				if knownFields[name] {
+					tokens = append(tokens, token{kind: tokField, text: name, pos: pos})
+					i += len([]rune(word[:colon])) + 1
+					continue
+				}
+				if quoted {
+					return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("field %q tidak dikenal", word[:colon])}
+				}
+			}
+			if word == "OR" {
+				tokens = append(tokens, token{kind: tokOr, text: word, pos: pos})
+			} else {
+				tokens = append(tokens, token{kind: tokWord, text: word, pos: pos})
+			}
+			i = end
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+func isBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '('
+}
+
+func isFieldName(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+type parser struct {
+	tokens []token
+	idx    int
+	plain  bool
+}
+
+// Parse mengurai string query menjadi pohon Query. Kesalahan selalu bertipe *SyntaxError.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, plain: true}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{Pos: 1, Msg: "query kosong"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("token %q tidak diharapkan", tok.text)}
+	}
+	return &Query{Root: root, plain: p.plain}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.idx]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.idx]
+	if tok.kind != tokEOF {
+		p.idx++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Node{first}
+	for p.peek().kind == tokOr {
+		p.plain = false
+		p.next()
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return Or{Terms: terms}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	var terms []Node
+	for {
+		switch p.peek().kind {
+		case tokEOF, tokOr, tokRParen:
+			if len(terms) == 0 {
+				tok := p.peek()
+				return nil, &SyntaxError{Pos: tok.pos, Msg: "term diharapkan"}
+			}
+			if len(terms) == 1 {
+				return terms[0], nil
+			}
+			return And{Terms: terms}, nil
+		}
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokMinus {
+		p.plain = false
+		p.next()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		p.plain = false
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "kurung buka tidak ditutup"}
+		}
+		return inner, nil
+	case tokWord:
+		return Term{Value: tok.text, Pos: tok.pos}, nil
+	case tokPhrase:
+		p.plain = false
+		return Term{Value: tok.text, Phrase: true, Pos: tok.pos}, nil
+	case tokField:
+		p.plain = false
+		return p.parseFieldValue(tok)
+	case tokRParen:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "kurung tutup tanpa pasangan"}
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "term diharapkan"}
+	}
+}
+
+func (p *parser) parseFieldValue(field token) (Node, error) {
+	value := p.next()
+	if value.kind != tokWord && value.kind != tokPhrase {
+		return nil, &SyntaxError{Pos: value.pos, Msg: fmt.Sprintf("nilai untuk field %q diharapkan", field.text)}
+	}
+	if field.text != FieldYear {
+		return Term{Field: field.text, Value: value.text, Phrase: value.kind == tokPhrase, Pos: field.pos}, nil
+	}
+
+	if from, to, ok := strings.Cut(value.text, ".."); ok {
+		rng := Range{Field: FieldYear, Pos: field.pos}
+		var err error
+		if rng.From, err = parseYearBound(from, value.pos); err != nil {
+			return nil, err
+		}
+		if rng.To, err = parseYearBound(to, value.pos+len([]rune(from))+2); err != nil {
+			return nil, err
+		}
+		if rng.From == nil && rng.To == nil {
+			return nil, &SyntaxError{Pos: value.pos, Msg: "rentang tahun membutuhkan minimal satu batas"}
+		}
+		if rng.From != nil && rng.To != nil && *rng.From > *rng.To {
+			return nil, &SyntaxError{Pos: value.pos, Msg: "batas awal rentang tahun lebih besar dari batas akhir"}
+		}
+		return rng, nil
+	}
+	if _, err := strconv.Atoi(value.text); err != nil {
+		return nil, &SyntaxError{Pos: value.pos, Msg: fmt.Sprintf("tahun %q bukan angka", value.text)}
+	}
+	return Term{Field: FieldYear, Value: value.text, Pos: field.pos}, nil
+}
+
+func parseYearBound(s string, pos int) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("batas tahun %q bukan angka", s)}
+	}
+	return &year, nil
+}
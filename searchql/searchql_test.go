@@ -0,0 +1,157 @@
+package searchql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func year(y int) *int {
+	return &y
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Node
+		plain bool
+	}{
+		{"dee hirata", And{Terms: []Node{Term{Value: "dee", Pos: 1}, Term{Value: "hirata", Pos: 5}}}, true},
+		{"laut-bercerita", Term{Value: "laut-bercerita", Pos: 1}, true},
+		// Rentang tahun, termasuk batas yang dikosongkan
+		{"year:2005..2012", Range{Field: FieldYear, From: year(2005), To: year(2012), Pos: 1}, false},
+		{"year:2005..", Range{Field: FieldYear, From: year(2005), Pos: 1}, false},
+		{"year:..2012", Range{Field: FieldYear, To: year(2012), Pos: 1}, false},
+		{"year:2005", Term{Field: FieldYear, Value: "2005", Pos: 1}, false},
+		// Negasi, OR, dan kurung
+		{"-title:filosofi", Not{Term: Term{Field: FieldTitle, Value: "filosofi", Pos: 2}}, false},
+		{"dee OR hirata", Or{Terms: []Node{Term{Value: "dee", Pos: 1}, Term{Value: "hirata", Pos: 8}}}, false},
+		{"a OR b c", Or{Terms: []Node{
+			Term{Value: "a", Pos: 1},
+			And{Terms: []Node{Term{Value: "b", Pos: 6}, Term{Value: "c", Pos: 8}}},
+		}}, false},
+		{"(dee OR hirata) -year:2005", And{Terms: []Node{
+			Or{Terms: []Node{Term{Value: "dee", Pos: 2}, Term{Value: "hirata", Pos: 9}}},
+			Not{Term: Term{Field: FieldYear, Value: "2005", Pos: 18}},
+		}}, false},
+		{"-(a OR b)", Not{Term: Or{Terms: []Node{Term{Value: "a", Pos: 3}, Term{Value: "b", Pos: 8}}}}, false},
+		// Frasa
+		{`"laskar pelangi"`, Term{Value: "laskar pelangi", Phrase: true, Pos: 1}, false},
+		{`author:"Dee Lestari" 2005`, And{Terms: []Node{
+			Term{Field: FieldAuthor, Value: "Dee Lestari", Phrase: true, Pos: 1},
+			Term{Value: "2005", Pos: 22},
+		}}, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(q.Root, tt.want) {
+			t.Errorf("Parse(%q) = %#v, seharusnya %#v", tt.input, q.Root, tt.want)
+		}
+		if q.Plain() != tt.plain {
+			t.Errorf("Parse(%q).Plain() = %v, seharusnya %v", tt.input, q.Plain(), tt.plain)
+		}
+	}
+}
+
+func TestParseSyntaxErrorPosition(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{"   ", 1},
+		{`""`, 1},
+		{`title:"dee`, 7},
+		{"(dee OR hirata", 1},
+		{"dee)", 4},
+		{"dee OR", 7},
+		{"dee ()", 6},
+		{"year:abc", 6},
+		{"year:..", 6},
+		{"year:2012..2005", 6},
+		{"year:2005..abc", 12},
+		{`genre:"fiksi ilmiah"`, 1},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, seharusnya *SyntaxError", tt.input, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos {
+			t.Errorf("Parse(%q) error di posisi %d (%v), seharusnya %d", tt.input, syntaxErr.Pos, err, tt.pos)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	const (
+		title  = `LOWER(title) LIKE LOWER($1) ESCAPE '\'`
+		either = `LOWER(title) LIKE LOWER($1) ESCAPE '\' OR LOWER(author) LIKE LOWER($1) ESCAPE '\'`
+	)
+	tests := []struct {
+		input    string
+		firstArg int
+		where    string
+		args     []interface{}
+	}{
+		{"year:2005..2012", 1, "(year >= $1 AND year <= $2)", []interface{}{2005, 2012}},
+		{"year:..2012", 3, "(year <= $3)", []interface{}{2012}},
+		{"year:2005", 1, "(year = $1)", []interface{}{2005}},
+		{"title:filosofi", 1, "(" + title + ")", []interface{}{"%filosofi%"}},
+		{"2005", 1, "(" + either + " OR year = $2)", []interface{}{"%2005%", 2005}},
+		{`"2005"`, 1, "(" + either + ")", []interface{}{"%2005%"}},
+		// Wildcard LIKE dari pengguna diloloskan
+		{"100%", 1, "(" + either + ")", []interface{}{`%100\%%`}},
+		{`author:a_b\c`, 1, `(LOWER(author) LIKE LOWER($1) ESCAPE '\')`, []interface{}{`%a\_b\\c%`}},
+		{"-title:dee OR hirata", 1,
+			`(NOT (LOWER(title) LIKE LOWER($1) ESCAPE '\') OR (LOWER(title) LIKE LOWER($2) ESCAPE '\' OR LOWER(author) LIKE LOWER($2) ESCAPE '\'))`,
+			[]interface{}{"%dee%", "%hirata%"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		where, args := Compile(q, tt.firstArg)
+		if where != tt.where {
+			t.Errorf("Compile(%q) = %s, seharusnya %s", tt.input, where, tt.where)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Compile(%q) args = %#v, seharusnya %#v", tt.input, args, tt.args)
+		}
+	}
+}
+
+func TestParseUnknownFieldPrefix(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Node
+	}{
+		{"Dune:Messiah", Term{Value: "Dune:Messiah", Pos: 1}},
+		{"sejarah:indonesia", Term{Value: "sejarah:indonesia", Pos: 1}},
+		{"Supernova: Ksatria", And{Terms: []Node{Term{Value: "Supernova:", Pos: 1}, Term{Value: "Ksatria", Pos: 12}}}},
+		{"Title:Dune", Term{Field: FieldTitle, Value: "Dune", Pos: 1}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(q.Root, tt.want) {
+			t.Errorf("Parse(%q) = %#v, seharusnya %#v", tt.input, q.Root, tt.want)
+		}
+	}
+
+	_, err := Parse(`genre:"fiksi ilmiah"`)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Pos != 1 {
+		t.Errorf(`Parse("genre:\"fiksi ilmiah\"") error = %v, seharusnya field tidak dikenal di posisi 1`, err)
+	}
+}
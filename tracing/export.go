@@ -0,0 +1,261 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Nama exporter yang didukung
+const (
+	ExporterNone     = "none"
+	ExporterStdout   = "stdout"
+	ExporterOTLPFile = "otlp-file"
+)
+
+// Exporter menerima span yang sudah selesai
+type Exporter interface {
+	Export(span *Span)
+	// Close mengirim sisa span yang tertahan dan melepaskan resource exporter
+	Close() error
+}
+
+var (
+	exporterMu  sync.RWMutex
+	exporter    Exporter
+	sampleRatio = 1.0
+	serviceName = "crud-buku-go"
+)
+
+// Enabled melaporkan apakah ada exporter aktif
+func Enabled() bool {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return exporter != nil
+}
+
+// Configure memasang exporter berdasarkan nama (none, stdout, otlp-file).
+// file dipakai oleh otlp-file; ratio adalah proporsi trace baru yang direkam.
+func Configure(name, file, service string, ratio float64) error {
+	var exp Exporter
+	switch name {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exp = newJSONExporter(os.Stdout, nil, false)
+	case ExporterOTLPFile:
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("gagal membuka file trace: %w", err)
+		}
+		exp = newJSONExporter(f, f, true)
+	default:
+		return fmt.Errorf("exporter trace %q tidak dikenal", name)
+	}
+
+	exporterMu.Lock()
+	old := exporter
+	exporter = exp
+	sampleRatio = ratio
+	serviceName = service
+	exporterMu.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Shutdown menutup exporter aktif; span yang berakhir sesudahnya tidak diekspor
+func Shutdown() error {
+	exporterMu.Lock()
+	old := exporter
+	exporter = nil
+	exporterMu.Unlock()
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// jsonExporter menulis satu span per baris. Dengan otlp bernilai true, setiap baris
+// berbentuk ExportTraceServiceRequest OTLP/JSON sehingga bisa dibaca oleh
+// OpenTelemetry Collector (receiver otlpjsonfile); selain itu berupa JSON ringkas.
+type jsonExporter struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	otlp   bool
+}
+
+func newJSONExporter(w io.Writer, closer io.Closer, otlp bool) *jsonExporter {
+	return &jsonExporter{w: bufio.NewWriter(w), closer: closer, otlp: otlp}
+}
+
+func (e *jsonExporter) Export(span *Span) {
+	span.mu.Lock()
+	var payload interface{}
+	if e.otlp {
+		payload = otlpRequest(span)
+	} else {
+		payload = simpleSpan(span)
+	}
+	span.mu.Unlock()
+
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(line)
+	e.w.WriteByte('\n')
+	e.w.Flush()
+}
+
+func (e *jsonExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	err := e.w.Flush()
+	if e.closer != nil {
+		if cerr := e.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func simpleSpan(span *Span) map[string]interface{} {
+	out := map[string]interface{}{
+		"trace_id":    span.Context.TraceID.String(),
+		"span_id":     span.Context.SpanID.String(),
+		"name":        span.Name,
+		"kind":        kindName(span.Kind),
+		"start":       span.StartTime,
+		"duration_ms": float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+		"attributes":  span.Attributes,
+	}
+	if span.Parent.IsValid() {
+		out["parent_id"] = span.Parent.String()
+	}
+	if span.Err != nil {
+		out["error"] = span.Err.Error()
+	}
+	return out
+}
+
+func kindName(k Kind) string {
+	switch k {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	}
+	return "internal"
+}
+
+// Struktur OTLP/JSON (opentelemetry-proto, trace/v1) yang dipakai exporter otlp-file
+type (
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+)
+
+// Kode status OTLP; span yang berhasil dibiarkan unset sesuai konvensi OpenTelemetry
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+func otlpRequest(span *Span) map[string]interface{} {
+	s := otlpSpan{
+		TraceID:           span.Context.TraceID.String(),
+		SpanID:            span.Context.SpanID.String(),
+		Name:              span.Name,
+		Kind:              int(span.Kind),
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		Attributes:        otlpAttributes(span.Attributes),
+		Status:            otlpStatus{Code: otlpStatusUnset},
+	}
+	if span.Parent.IsValid() {
+		s.ParentSpanID = span.Parent.String()
+	}
+	if span.Err != nil {
+		s.Status = otlpStatus{Code: otlpStatusError, Message: span.Err.Error()}
+	}
+
+	exporterMu.RLock()
+	service := serviceName
+	exporterMu.RUnlock()
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributes(map[string]interface{}{"service.name": service}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": "crud-buku-go/tracing"},
+				"spans": []otlpSpan{s},
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs map[string]interface{}) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		var v otlpAnyValue
+		switch value := attrs[key].(type) {
+		case string:
+			v.StringValue = &value
+		case int:
+			s := strconv.Itoa(value)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(value, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &value
+		case bool:
+			v.BoolValue = &value
+		default:
+			s := fmt.Sprint(value)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKeyValue{Key: key, Value: v})
+	}
+	return out
+}
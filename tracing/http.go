@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// TraceparentHeader adalah nama header W3C Trace Context
+const TraceparentHeader = "traceparent"
+
+// Extract mengambil induk remote dari header traceparent request, jika valid
+func Extract(ctx context.Context, header http.Header) context.Context {
+	raw := header.Get(TraceparentHeader)
+	if raw == "" {
+		return ctx
+	}
+	sc, err := ParseTraceparent(raw)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteParent(ctx, sc)
+}
+
+// Inject menulis header traceparent untuk span aktif di ctx ke header
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		header.Set(TraceparentHeader, span.Context.Traceparent())
+		return
+	}
+	// Tracing tidak aktif: teruskan induk remote apa adanya agar trace tidak terputus
+	if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok && remote.IsValid() {
+		header.Set(TraceparentHeader, remote.Traceparent())
+	}
+}
+
+// Transport adalah http.RoundTripper yang membuat span client untuk setiap
+// request keluar dan menambahkan header traceparent
+type Transport struct {
+	// Base adalah transport yang dibungkus; nil berarti http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// RoundTrip menjalankan request keluar di dalam span client
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Start(req.Context(), "HTTP "+req.Method, KindClient)
+	defer span.End()
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("url.full", req.URL.Redacted())
+	span.SetAttribute("server.address", req.URL.Hostname())
+
+	// RoundTripper tidak boleh mengubah request asli
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= 500 {
+		span.RecordError(&statusError{resp.StatusCode})
+	}
+	return resp, nil
+}
+
+// statusError menandai span HTTP gagal karena status 5xx
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return "HTTP " + strconv.Itoa(e.code) + " " + http.StatusText(e.code)
+}
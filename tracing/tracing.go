@@ -0,0 +1,226 @@
+// Package tracing menyediakan tracing bergaya OpenTelemetry tanpa dependensi luar:
+// span bersarang lewat context, propagasi header W3C traceparent, dan exporter
+// yang bisa dipilih (stdout atau file OTLP/JSON).
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceID dan SpanID mengikuti ukuran W3C Trace Context
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+// IsValid melaporkan apakah ID tidak bernilai nol semua
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// IsValid melaporkan apakah ID tidak bernilai nol semua
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext adalah identitas span yang dipropagasikan antar proses
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid melaporkan apakah trace ID dan span ID terisi
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Kind span sesuai semantik OpenTelemetry
+type Kind int
+
+// Nilai Kind mengikuti enum SpanKind pada OTLP
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+// Span adalah satu unit pekerjaan yang diukur. Span nil aman dipakai (no-op),
+// sehingga pemanggil tidak perlu memeriksa apakah tracing aktif.
+type Span struct {
+	Name       string
+	Kind       Kind
+	Context    SpanContext
+	Parent     SpanID
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]interface{}
+	Err        error
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// SetAttribute menambahkan atribut ke span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// RecordError menandai span gagal dengan err; err nil diabaikan
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err
+}
+
+// End mengakhiri span dan mengirimkannya ke exporter. Panggilan berikutnya diabaikan.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	if s.Context.Sampled {
+		exporterMu.RLock()
+		exp := exporter
+		exporterMu.RUnlock()
+		if exp != nil {
+			exp.Export(s)
+		}
+	}
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// SpanFromContext mengembalikan span aktif di ctx, atau nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent menyimpan span context dari proses lain (misalnya dari
+// header traceparent) sebagai induk span berikutnya
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// Start memulai span baru sebagai anak span aktif di ctx (atau induk remote).
+// Jika tracing tidak aktif, span yang dikembalikan bernilai nil.
+func Start(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	span := &Span{Name: name, Kind: kind, StartTime: time.Now(), Attributes: make(map[string]interface{})}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.Context.TraceID = parent.Context.TraceID
+		span.Context.Sampled = parent.Context.Sampled
+		span.Parent = parent.Context.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok && remote.IsValid() {
+		span.Context.TraceID = remote.TraceID
+		span.Context.Sampled = remote.Sampled
+		span.Parent = remote.SpanID
+	} else {
+		span.Context.TraceID = newTraceID()
+		span.Context.Sampled = sample()
+	}
+	span.Context.SpanID = newSpanID()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// ParseTraceparent membaca header W3C traceparent, misalnya
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceparent(header string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, errors.New("traceparent tidak valid")
+	}
+	// Versi 00 harus tepat 4 bagian; versi lebih baru boleh menambah bagian
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, errors.New("traceparent tidak valid")
+	}
+
+	var sc SpanContext
+	if err := decodeHex(parts[1], sc.TraceID[:]); err != nil || !sc.TraceID.IsValid() {
+		return SpanContext{}, errors.New("trace-id pada traceparent tidak valid")
+	}
+	if err := decodeHex(parts[2], sc.SpanID[:]); err != nil || !sc.SpanID.IsValid() {
+		return SpanContext{}, errors.New("parent-id pada traceparent tidak valid")
+	}
+	var flags [1]byte
+	if err := decodeHex(parts[3], flags[:]); err != nil {
+		return SpanContext{}, errors.New("trace-flags pada traceparent tidak valid")
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, nil
+}
+
+// Traceparent mengembalikan header W3C traceparent untuk span context ini
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+func decodeHex(s string, dst []byte) error {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return errors.New("panjang atau huruf hex tidak valid")
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// sample memutuskan apakah trace baru direkam berdasarkan rasio sampling
+func sample() bool {
+	exporterMu.RLock()
+	ratio := sampleRatio
+	exporterMu.RUnlock()
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return mathrand.Float64() < ratio
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"crud-buku-go/logging"
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType adalah media type body error (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Jenis problem yang membawa extension member. Problem lain memakai about:blank
+// sehingga artinya cukup dibaca dari status HTTP.
+const (
+	ProblemTypeBlank             = "about:blank"
+	ProblemTypeValidation        = "urn:crud-buku:problem:validation"
+	ProblemTypeRateLimited       = "urn:crud-buku:problem:rate-limited"
+	ProblemTypeMissingPermission = "urn:crud-buku:problem:missing-permission"
+	ProblemTypeQuerySyntax       = "urn:crud-buku:problem:query-syntax"
+	ProblemTypeSearchBackend     = "urn:crud-buku:problem:search-backend"
+)
+
+// @Description Detail kesalahan dalam format RFC 7807 (application/problem+json)
+// Problem adalah body response error sesuai RFC 7807. Extensions ditulis sejajar dengan
+// member standar, misalnya daftar kesalahan per field pada problem validasi.
+type Problem struct {
+	Type       string                 `json:"type" example:"about:blank"`
+	Title      string                 `json:"title" example:"Not Found"`
+	Status     int                    `json:"status" example:"404"`
+	Detail     string                 `json:"detail,omitempty" example:"buku tidak ditemukan"`
+	Instance   string                 `json:"instance,omitempty" example:"/api/books/42"`
+	RequestID  string                 `json:"request_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// NewProblem membuat problem about:blank dengan judul dari status HTTP
+func NewProblem(status int, detail string) Problem {
+	return Problem{Type: ProblemTypeBlank, Title: http.StatusText(status), Status: status, Detail: detail}
+}
+
+// With menambahkan extension member dan mengembalikan p
+func (p Problem) With(name string, value interface{}) Problem {
+	extensions := make(map[string]interface{}, len(p.Extensions)+1)
+	for k, v := range p.Extensions {
+		extensions[k] = v
+	}
+	extensions[name] = value
+	p.Extensions = extensions
+	return p
+}
+
+// MarshalJSON menulis member standar beserta extension; extension tidak bisa menimpa
+// member standar
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	body, err := json.Marshal(standard(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+	fields := make(map[string]json.RawMessage, len(p.Extensions)+6)
+	for name, value := range p.Extensions {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = raw
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, err
+	}
+	for name, raw := range members {
+		fields[name] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// RespondWithProblem mengirimkan problem sebagai application/problem+json. Instance
+// diisi path request dan request ID dari context jika belum diisi.
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+	if problem.RequestID == "" {
+		problem.RequestID = logging.RequestID(r.Context())
+	}
+	response, _ := json.Marshal(problem)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(response)
+}
@@ -5,9 +5,10 @@ import (
 	"net/http"
 )
 
-// RespondWithError mengirimkan response error dalam format JSON
-func RespondWithError(w http.ResponseWriter, code int, message string) {
-	RespondWithJSON(w, code, map[string]string{"error": message})
+// RespondWithError mengirimkan response error sebagai problem about:blank dengan
+// message sebagai detail. message harus aman dibaca klien; jangan isi dengan err.Error().
+func RespondWithError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	RespondWithProblem(w, r, NewProblem(code, message))
 }
 
 // RespondWithJSON mengirimkan response dalam format JSON
@@ -16,4 +17,4 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	w.Write(response)
-}
\ No newline at end of file
+}